@@ -23,3 +23,12 @@ type OratabEntry struct {
 	DBName     string
 	OracleHome string
 }
+
+// ClusterwareService is the configuration of a database service registered in Grid Infrastructure
+type ClusterwareService struct {
+	Name               string
+	PDB                string
+	FailoverType       string
+	PreferredInstances []string
+	AvailableInstances []string
+}
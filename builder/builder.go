@@ -16,22 +16,50 @@
 package builder
 
 import (
+	"fmt"
+	"strings"
+
 	common "github.com/ercole-io/ercole-agent-rhel5/builder/common_builder"
 	"github.com/ercole-io/ercole-agent-rhel5/config"
 	"github.com/ercole-io/ercole-agent-rhel5/logger"
 	"github.com/ercole-io/ercole-agent-rhel5/model"
 )
 
-// BuildData will build HostData
-func BuildData(configuration config.Configuration, log logger.Logger) *model.HostData {
+// BuildData will build HostData, the collection is canceled when cancel is closed
+func BuildData(configuration config.Configuration, log logger.Logger, cancel <-chan struct{}) *model.HostData {
 	hostData := new(model.HostData)
 
 	hostData.Location = configuration.Location
 	hostData.Environment = configuration.Environment
 
-	builder := common.NewCommonBuilder(configuration, log)
+	builder := common.NewCommonBuilder(configuration, log, cancel)
 
 	builder.Run(hostData)
 
+	logCollectionSummary(log, hostData.CollectionMetadata)
+
 	return hostData
 }
+
+// logCollectionSummary log a table with the time spent by each section, from the slowest
+func logCollectionSummary(log logger.Logger, metadata *model.CollectionMetadata) {
+	if metadata == nil {
+		return
+	}
+
+	lines := make([]string, 0, len(metadata.Sections)+len(metadata.Databases)+3)
+	lines = append(lines, fmt.Sprintf("%-30s %-20s %6s %12s", "SECTION", "DATABASE", "CALLS", "DURATION"))
+
+	for _, section := range metadata.Sections {
+		lines = append(lines, fmt.Sprintf("%-30s %-20s %6d %11.2fs",
+			section.Section, section.Database, section.Calls, section.Duration))
+	}
+
+	for _, database := range metadata.Databases {
+		lines = append(lines, fmt.Sprintf("Database [%s] collected in %.2fs", database.Database, database.Duration))
+	}
+
+	lines = append(lines, fmt.Sprintf("Collection completed in %.2fs", metadata.Duration))
+
+	log.Infof("Collection summary:\n%s", strings.Join(lines, "\n"))
+}
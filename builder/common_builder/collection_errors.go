@@ -0,0 +1,84 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ercole-io/ercole-agent-rhel5/fetcher"
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+// collectionErrors accumulates the errors of the sections that couldn't be collected
+type collectionErrors struct {
+	lock   sync.Mutex
+	errors []model.CollectionError
+}
+
+func newCollectionErrors() *collectionErrors {
+	return &collectionErrors{errors: make([]model.CollectionError, 0)}
+}
+
+func (c *collectionErrors) add(section, dbName string, err error) {
+	collectionError := model.CollectionError{
+		Section:  section,
+		Database: dbName,
+		Class:    model.CollectionErrorClassGeneric,
+		Message:  err.Error(),
+	}
+
+	switch e := err.(type) {
+	case *fetcher.FetchError:
+		collectionError.Fetcher = e.Fetcher
+		collectionError.Class = e.Class
+		collectionError.Duration = e.Duration.Seconds()
+	case *fetcher.IntegrityError:
+		collectionError.Fetcher = e.Fetcher
+		collectionError.Class = model.CollectionErrorClassIntegrity
+	case *fetcher.StatfsError:
+		collectionError.Class = model.CollectionErrorClassTimeout
+	case *fetcher.MarshalError:
+		collectionError.Fetcher = e.Fetcher
+		collectionError.Class = model.CollectionErrorClassMarshal
+	case *panicError:
+		collectionError.Class = model.CollectionErrorClassPanic
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.errors = append(c.errors, collectionError)
+}
+
+func (c *collectionErrors) list() []model.CollectionError {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	errors := make([]model.CollectionError, len(c.errors))
+	copy(errors, c.errors)
+
+	return errors
+}
+
+// panicError wraps a value recovered from a panic
+type panicError struct {
+	value interface{}
+}
+
+func (e *panicError) Error() string {
+	return fmt.Sprintf("Panic: %v", e.value)
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+// collectionTimings accumulates the time spent by the fetchers, per section and per database
+type collectionTimings struct {
+	lock      sync.Mutex
+	sections  map[sectionKey]*model.CollectionSectionTiming
+	databases map[string]time.Duration
+}
+
+type sectionKey struct {
+	section, dbName string
+}
+
+func newCollectionTimings() *collectionTimings {
+	return &collectionTimings{
+		sections:  make(map[sectionKey]*model.CollectionSectionTiming),
+		databases: make(map[string]time.Duration),
+	}
+}
+
+func (c *collectionTimings) addSection(section, dbName string, duration time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	key := sectionKey{section, dbName}
+
+	timing, ok := c.sections[key]
+	if !ok {
+		timing = &model.CollectionSectionTiming{Section: section, Database: dbName}
+		c.sections[key] = timing
+	}
+
+	timing.Calls++
+	timing.Duration += duration.Seconds()
+}
+
+func (c *collectionTimings) addDatabase(dbName string, duration time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.databases[dbName] += duration
+}
+
+// metadata return the timings sorted from the slowest
+func (c *collectionTimings) metadata(duration time.Duration) *model.CollectionMetadata {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	metadata := &model.CollectionMetadata{
+		Duration:  duration.Seconds(),
+		Sections:  make([]model.CollectionSectionTiming, 0, len(c.sections)),
+		Databases: make([]model.CollectionDatabaseTiming, 0, len(c.databases)),
+	}
+
+	for _, timing := range c.sections {
+		metadata.Sections = append(metadata.Sections, *timing)
+	}
+	sort.Sort(bySectionDuration(metadata.Sections))
+
+	for dbName, dbDuration := range c.databases {
+		metadata.Databases = append(metadata.Databases, model.CollectionDatabaseTiming{
+			Database: dbName,
+			Duration: dbDuration.Seconds(),
+		})
+	}
+	sort.Sort(byDatabaseDuration(metadata.Databases))
+
+	return metadata
+}
+
+type bySectionDuration []model.CollectionSectionTiming
+
+func (s bySectionDuration) Len() int           { return len(s) }
+func (s bySectionDuration) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s bySectionDuration) Less(i, j int) bool { return s[i].Duration > s[j].Duration }
+
+type byDatabaseDuration []model.CollectionDatabaseTiming
+
+func (s byDatabaseDuration) Len() int           { return len(s) }
+func (s byDatabaseDuration) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byDatabaseDuration) Less(i, j int) bool { return s[i].Duration > s[j].Duration }
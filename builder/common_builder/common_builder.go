@@ -18,6 +18,7 @@ package common
 import (
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/ercole-io/ercole-agent-rhel5/config"
 	"github.com/ercole-io/ercole-agent-rhel5/fetcher"
@@ -28,13 +29,19 @@ import (
 
 // CommonBuilder for Linux and Windows hosts
 type CommonBuilder struct {
-	fetcher       fetcher.Fetcher
-	configuration config.Configuration
-	log           logger.Logger
+	fetcher          fetcher.Fetcher
+	configuration    config.Configuration
+	log              logger.Logger
+	collectionErrors *collectionErrors
+	timings          *collectionTimings
+	pool             *utils.WorkerPool
+	// fetchers bounds the fetchers running at the same time on the host, whatever database they collect
+	fetchers *utils.Limiter
 }
 
-// NewCommonBuilder initialize an appropriate builder for Linux or Windows
-func NewCommonBuilder(configuration config.Configuration, log logger.Logger) CommonBuilder {
+// NewCommonBuilder initialize an appropriate builder for Linux or Windows,
+// closing cancel kills the running fetchers and makes the next ones fail
+func NewCommonBuilder(configuration config.Configuration, log logger.Logger, cancel <-chan struct{}) CommonBuilder {
 	var f fetcher.Fetcher
 
 	log.Debugf("runtime.GOOS: [%v]", runtime.GOOS)
@@ -43,12 +50,16 @@ func NewCommonBuilder(configuration config.Configuration, log logger.Logger) Com
 		log.Errorf("Unknow runtime.GOOS: [%v], I'll try with linux\n", runtime.GOOS)
 	}
 
-	f = fetcher.NewLinuxFetcherImpl(configuration, log)
+	f = fetcher.NewLinuxFetcherImpl(configuration, log, cancel)
 
 	builder := CommonBuilder{
-		fetcher:       f,
-		configuration: configuration,
-		log:           log,
+		fetcher:          f,
+		configuration:    configuration,
+		log:              log,
+		collectionErrors: newCollectionErrors(),
+		timings:          newCollectionTimings(),
+		pool:             utils.NewWorkerPool(configuration, configuration.MaxParallelRequests),
+		fetchers:         utils.NewLimiter(configuration.MaxParallelRequests),
 	}
 
 	return builder
@@ -56,17 +67,32 @@ func NewCommonBuilder(configuration config.Configuration, log logger.Logger) Com
 
 // Run fill hostData
 func (b *CommonBuilder) Run(hostData *model.HostData) {
-	var err error
+	start := time.Now()
+
 	// build data about host info
-	hostData.Info = b.fetcher.GetHost()
-	if hostData.Filesystems, err = b.fetcher.GetFilesystems(); err != nil {
-		b.log.Error(err)
+	b.fetch("host", "", func() (err error) {
+		hostData.Info, err = b.fetcher.GetHost()
+		return err
+	})
+
+	err := b.fetch("filesystems", "", func() (err error) {
+		hostData.Filesystems, err = b.fetcher.GetFilesystems()
+		return err
+	})
+	// On a *fetcher.StatfsError the filesystems which answered are kept
+	if err != nil && hostData.Filesystems == nil {
+		hostData.Filesystems = []model.Filesystem{}
 	}
+
 	hostData.Hostname = hostData.Info.Hostname
 	if b.configuration.Hostname != "default" {
 		hostData.Hostname = b.configuration.Hostname
 	}
-	hostData.ClusterMembershipStatus = b.fetcher.GetClustersMembershipStatus()
+
+	b.fetch("clusterMembershipStatus", "", func() (err error) {
+		hostData.ClusterMembershipStatus, err = b.fetcher.GetClustersMembershipStatus()
+		return err
+	})
 
 	// build data about Oracle/Database
 	if b.configuration.Features.OracleDatabase.Enabled {
@@ -87,6 +113,18 @@ func (b *CommonBuilder) Run(hostData *model.HostData) {
 		hostData.Features.Oracle.Exadata = b.getOracleExadataFeature()
 	}
 
+	// build data about Oracle/ASM
+	if b.configuration.Features.OracleASM.Enabled {
+		b.log.Debugf("Oracle/ASM mode enabled (user='%s')", b.configuration.Features.OracleASM.FetcherUser)
+		b.setOrResetFetcherUser(b.configuration.Features.OracleASM.FetcherUser)
+
+		asm := b.getOracleASMFeature()
+		if asm != nil {
+			lazyInitOracleFeature(&hostData.Features)
+			hostData.Features.Oracle.ASM = asm
+		}
+	}
+
 	// build data about Virtualization
 	if b.configuration.Features.Virtualization.Enabled {
 		b.log.Debugf("Virtualization mode enabled (user='%s')", b.configuration.Features.Virtualization.FetcherUser)
@@ -94,6 +132,26 @@ func (b *CommonBuilder) Run(hostData *model.HostData) {
 
 		hostData.Clusters = b.getClustersInfos()
 	}
+
+	hostData.CollectionErrors = b.collectionErrors.list()
+	hostData.CollectionMetadata = b.timings.metadata(time.Since(start))
+}
+
+// fetch run and time the fetcher calls of a section of a database (if any).
+// On failure the error is reported and returned: the caller skips the section.
+func (b *CommonBuilder) fetch(section, dbName string, fetch func() error) error {
+	var err error
+	b.fetchers.Do(func() {
+		start := time.Now()
+		err = fetch()
+		b.timings.addSection(section, dbName, time.Since(start))
+	})
+
+	if err != nil {
+		b.sectionError(section, dbName, err)
+	}
+
+	return err
 }
 
 func (b *CommonBuilder) checksToRunExadata() {
@@ -126,6 +184,19 @@ func (b *CommonBuilder) setOrResetFetcherUser(user string) {
 	}
 }
 
+// sectionError log and report in the hostdata the error of a fetcher,
+// its section is skipped and the collection goes on
+func (b *CommonBuilder) sectionError(section, dbName string, err error) {
+	b.collectionErrors.add(section, dbName, err)
+
+	if dbName == "" {
+		b.log.Errorf("Can't collect [%s], section skipped: %v", section, err)
+		return
+	}
+
+	b.log.Errorf("Can't collect [%s] of database [%s], section skipped: %v", section, dbName, err)
+}
+
 func lazyInitOracleFeature(fs *model.Features) {
 	if fs.Oracle == nil {
 		fs.Oracle = new(model.OracleFeature)
@@ -0,0 +1,38 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+// getOracleASMFeature returns nil if there's no ASM instance or it can't be collected
+func (b *CommonBuilder) getOracleASMFeature() *model.OracleASMFeature {
+	var asm *model.OracleASMFeature
+	err := b.fetch("asm", "", func() (err error) {
+		asm, err = b.fetcher.GetOracleASM()
+		return err
+	})
+	if err != nil {
+		return nil
+	}
+
+	if asm == nil {
+		b.log.Debugf("No ASM instance in oratab [%s]", b.configuration.Features.OracleDatabase.Oratab)
+	}
+
+	return asm
+}
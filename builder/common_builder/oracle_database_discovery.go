@@ -0,0 +1,76 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"github.com/ercole-io/ercole-agent-rhel5/agentmodel"
+)
+
+// addDiscoveredOracleDBs returns oratabEntries with the running instances and the ones registered
+// in Grid Infrastructure that are missing from it, if their discovery is enabled
+func (b *CommonBuilder) addDiscoveredOracleDBs(oratabEntries []agentmodel.OratabEntry) []agentmodel.OratabEntry {
+	conf := b.configuration.Features.OracleDatabase
+
+	entries := make([]agentmodel.OratabEntry, len(oratabEntries))
+	copy(entries, oratabEntries)
+
+	known := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		known[entry.DBName] = true
+	}
+
+	add := func(source string, discovered []agentmodel.OratabEntry) {
+		for _, entry := range discovered {
+			if known[entry.DBName] {
+				continue
+			}
+
+			if entry.OracleHome == "" {
+				b.log.Warnf("Can't resolve ORACLE_HOME of database [%s] discovered from %s, skipped", entry.DBName, source)
+				continue
+			}
+
+			b.log.Infof("Database [%s] with ORACLE_HOME [%s] discovered from %s", entry.DBName, entry.OracleHome, source)
+			known[entry.DBName] = true
+			entries = append(entries, entry)
+		}
+	}
+
+	// Running instances are added first, their ORACLE_HOME is the one actually in use
+	if conf.DiscoverRunningDatabases {
+		var running []agentmodel.OratabEntry
+		err := b.fetch("runningDatabaseHomes", "", func() (err error) {
+			running, err = b.fetcher.GetOracleDatabaseRunningHomes()
+			return err
+		})
+		if err == nil {
+			add("pmon process", running)
+		}
+	}
+
+	if conf.DiscoverClusterwareDatabases {
+		var registered []agentmodel.OratabEntry
+		err := b.fetch("clusterwareDatabases", "", func() (err error) {
+			registered, err = b.fetcher.GetOracleDatabaseClusterwareDatabases()
+			return err
+		})
+		if err == nil {
+			add("Grid Infrastructure", registered)
+		}
+	}
+
+	return entries
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/ercole-io/ercole-agent-rhel5/agentmodel"
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+// filterOratabEntries returns the entries selected by the Include and Exclude filters of the configuration
+// and the ones skipped, with the reason
+func (b *CommonBuilder) filterOratabEntries(entries []agentmodel.OratabEntry) ([]agentmodel.OratabEntry, []model.OracleDatabaseSkippedDatabase) {
+	include := b.configuration.Features.OracleDatabase.Include
+	exclude := b.configuration.Features.OracleDatabase.Exclude
+
+	selected := make([]agentmodel.OratabEntry, 0, len(entries))
+	skipped := []model.OracleDatabaseSkippedDatabase{}
+
+	for _, entry := range entries {
+		reason := ""
+
+		switch {
+		case len(include.DBNames) > 0 && !matchesAny(include.DBNames, entry.DBName):
+			reason = fmt.Sprintf("DB name [%s] doesn't match any Include.DBNames pattern", entry.DBName)
+		case len(include.OracleHomes) > 0 && !matchesAny(include.OracleHomes, entry.OracleHome):
+			reason = fmt.Sprintf("ORACLE_HOME [%s] doesn't match any Include.OracleHomes pattern", entry.OracleHome)
+		case matchesAny(exclude.DBNames, entry.DBName):
+			reason = fmt.Sprintf("DB name [%s] matches an Exclude.DBNames pattern", entry.DBName)
+		case matchesAny(exclude.OracleHomes, entry.OracleHome):
+			reason = fmt.Sprintf("ORACLE_HOME [%s] matches an Exclude.OracleHomes pattern", entry.OracleHome)
+		}
+
+		if reason == "" {
+			selected = append(selected, entry)
+			continue
+		}
+
+		b.log.Infof("Database [%s] skipped: %s", entry.DBName, reason)
+		skipped = append(skipped, model.OracleDatabaseSkippedDatabase{
+			Name:       entry.DBName,
+			OracleHome: entry.OracleHome,
+			Reason:     reason,
+		})
+	}
+
+	return selected, skipped
+}
+
+// disabledSections returns the sections disabled by the configuration for dbName
+func (b *CommonBuilder) disabledSections(dbName string) map[string]bool {
+	disabled := make(map[string]bool)
+
+	for pattern, sections := range b.configuration.Features.OracleDatabase.DisabledSections {
+		if !matchesAny([]string{pattern}, dbName) {
+			continue
+		}
+
+		for _, section := range sections {
+			disabled[section] = true
+		}
+	}
+
+	return disabled
+}
+
+// addSections adds the sections not collected for other reasons, e.g. the database-wide ones of a cluster database
+func addSections(sections map[string]bool, added []string) {
+	for _, section := range added {
+		sections[section] = true
+	}
+}
+
+func sortedSections(sections map[string]bool) []string {
+	sorted := make([]string, 0, len(sections))
+	for section := range sections {
+		sorted = append(sorted, section)
+	}
+	sort.Strings(sorted)
+
+	return sorted
+}
+
+// matchesAny patterns are validated reading the configuration
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, value); matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -16,10 +16,13 @@
 package common
 
 import (
+	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/ercole-io/ercole-agent-rhel5/agentmodel"
+	"github.com/ercole-io/ercole-agent-rhel5/config"
 	"github.com/ercole-io/ercole-agent-rhel5/model"
 	"github.com/ercole-io/ercole-agent-rhel5/utils"
 )
@@ -27,23 +30,43 @@ import (
 func (b *CommonBuilder) getOracleDatabaseFeature(host model.Host) *model.OracleDatabaseFeature {
 	oracleDatabaseFeature := new(model.OracleDatabaseFeature)
 
-	oratabEntries := b.fetcher.GetOracleDatabaseOratabEntries()
+	var oratabEntries []agentmodel.OratabEntry
+	err := b.fetch("oratab", "", func() (err error) {
+		oratabEntries, err = b.fetcher.GetOracleDatabaseOratabEntries()
+		return err
+	})
+	if err != nil {
+		oratabEntries = []agentmodel.OratabEntry{}
+	}
+
 	oracleDatabaseFeature.UnlistedRunningDatabases = b.getUnlistedRunningOracleDBs(oratabEntries)
 
+	oratabEntries = b.addDiscoveredOracleDBs(oratabEntries)
+
+	oratabEntries, oracleDatabaseFeature.SkippedDatabases = b.filterOratabEntries(oratabEntries)
+
 	oracleDatabaseFeature.Databases = b.getOracleDBs(oratabEntries, host)
 
 	return oracleDatabaseFeature
 }
 
 func (b *CommonBuilder) getUnlistedRunningOracleDBs(oratabEntries []agentmodel.OratabEntry) []string {
-	runningDBs := b.fetcher.GetOracleDatabaseRunningDatabases()
+	unlistedRunningDBs := make([]string, 0)
+
+	var runningDBs []string
+	err := b.fetch("unlistedRunningDatabases", "", func() (err error) {
+		runningDBs, err = b.fetcher.GetOracleDatabaseRunningDatabases()
+		return err
+	})
+	if err != nil {
+		return unlistedRunningDBs
+	}
 
 	oratabEntriesNames := make(map[string]bool, len(oratabEntries))
 	for _, db := range oratabEntries {
 		oratabEntriesNames[db.DBName] = true
 	}
 
-	unlistedRunningDBs := make([]string, 0)
 	for _, runningDB := range runningDBs {
 		if !oratabEntriesNames[runningDB] {
 			unlistedRunningDBs = append(unlistedRunningDBs, runningDB)
@@ -60,10 +83,10 @@ func (b *CommonBuilder) getOracleDBs(oratabEntries []agentmodel.OratabEntry, hos
 	for i := range oratabEntries {
 		entry := oratabEntries[i]
 
-		utils.RunRoutine(b.configuration, func() {
+		b.pool.Run(func() {
 			b.log.Debugf("oratab entry: [%v]", entry)
 
-			databaseChannel <- b.getOracleDB(entry, host)
+			databaseChannel <- b.getOracleDBRecovering(entry, host)
 		})
 	}
 
@@ -78,31 +101,51 @@ func (b *CommonBuilder) getOracleDBs(oratabEntries []agentmodel.OratabEntry, hos
 	return databases
 }
 
+// getOracleDBRecovering contains any panic raised collecting a database, which is skipped
+func (b *CommonBuilder) getOracleDBRecovering(entry agentmodel.OratabEntry, host model.Host) (database *model.OracleDatabase) {
+	start := time.Now()
+	defer func() {
+		b.timings.addDatabase(entry.DBName, time.Since(start))
+	}()
+
+	defer func() {
+		if r := recover(); r != nil {
+			b.sectionError("database", entry.DBName, &panicError{r})
+			database = nil
+		}
+	}()
+
+	return b.getOracleDB(entry, host)
+}
+
 func (b *CommonBuilder) getOracleDB(entry agentmodel.OratabEntry, host model.Host) *model.OracleDatabase {
-	dbStatus := b.fetcher.GetOracleDatabaseDbStatus(entry)
+	var dbStatus string
+	err := b.fetch("dbStatus", entry.DBName, func() (err error) {
+		dbStatus, err = b.fetcher.GetOracleDatabaseDbStatus(entry)
+		return err
+	})
+	if err != nil {
+		return nil
+	}
+
 	var database *model.OracleDatabase
+	var clusterDatabase bool
+	var instances []model.OracleDatabaseInstance
 
 	switch {
 	case dbStatus == "READ WRITE" || dbStatus == "READ ONLY":
-		database = b.getOpenDatabase(entry, host.HardwareAbstractionTechnology)
+		clusterDatabase, instances = b.getOracleDBInstances(entry)
+		database = b.getOpenDatabase(entry, host.HardwareAbstractionTechnology,
+			b.collectsDatabaseWideSections(entry, clusterDatabase, instances))
+		if database == nil {
+			return nil
+		}
 	case dbStatus == "MOUNTED" || dbStatus == "READ ONLY WITH APPLY":
-		{
-			db := b.fetcher.GetOracleDatabaseMountedDb(entry)
-			database = &db
-
-			database.Tablespaces = []model.OracleDatabaseTablespace{}
-			database.Schemas = []model.OracleDatabaseSchema{}
-			database.Patches = []model.OracleDatabasePatch{}
-			database.ADDMs = []model.OracleDatabaseAddm{}
-			database.SegmentAdvisors = []model.OracleDatabaseSegmentAdvisor{}
-			database.PSUs = []model.OracleDatabasePSU{}
-			database.Backups = []model.OracleDatabaseBackup{}
-			database.PDBs = []model.OracleDatabasePluggableDatabase{}
-			database.Services = []model.OracleDatabaseService{}
-			database.FeatureUsageStats = []model.OracleDatabaseFeatureUsageStat{}
-			database.Partitionings = []model.OracleDatabasePartitioning{}
-
-			database.Licenses = computeLicenses(database.Edition(), database.CoreFactor(host), host.CPUCores)
+		clusterDatabase, instances = b.getOracleDBInstances(entry)
+		database = b.getMountedDatabase(entry, host,
+			b.collectsDatabaseWideSections(entry, clusterDatabase, instances))
+		if database == nil {
+			return nil
 		}
 	default:
 		if strings.Contains(dbStatus, "ORA-01034") {
@@ -110,76 +153,318 @@ func (b *CommonBuilder) getOracleDB(entry agentmodel.OratabEntry, host model.Hos
 			return nil
 		}
 
-		b.log.Errorf("Unknown dbStatus: [%s] DBName: [%s] OracleHome: [%s]",
-			dbStatus, entry.DBName, entry.OracleHome)
+		b.sectionError("database", entry.DBName,
+			fmt.Errorf("Unknown dbStatus: [%s] DBName: [%s] OracleHome: [%s]", dbStatus, entry.DBName, entry.OracleHome))
 		return nil
 	}
 
-	grantsDba := b.fetcher.GetOracleDatabaseGrantsDba(entry)
+	database.IsRAC = clusterDatabase
+	database.Instances = instances
 
-	database.GrantDba = grantsDba
+	if (database.Dataguard || database.Role != "PRIMARY") && !hasSkippedSection(database, "dataguard") {
+		b.fetch("dataguard", entry.DBName, func() (err error) {
+			database.DataguardStatus, err = b.fetcher.GetOracleDatabaseDataguard(entry)
+			return err
+		})
+	}
+
+	// The data dictionary isn't available on mounted databases
+	if dbStatus == "MOUNTED" || hasSkippedSection(database, "grantDba") {
+		database.GrantDba = []model.OracleGrantDba{}
+		return database
+	}
+
+	err = b.fetch("grantDba", entry.DBName, func() (err error) {
+		database.GrantDba, err = b.fetcher.GetOracleDatabaseGrantsDba(entry)
+		return err
+	})
+	if err != nil {
+		database.GrantDba = []model.OracleGrantDba{}
+	}
+
+	return database
+}
+
+// getMountedDatabase collects a mounted database, usually a standby, with the sections that can be read
+// from the controlfile. Only its per-node sections if databaseWideSections is false
+func (b *CommonBuilder) getMountedDatabase(entry agentmodel.OratabEntry, host model.Host, databaseWideSections bool) *model.OracleDatabase {
+	database := new(model.OracleDatabase)
+	err := b.fetch("database", entry.DBName, func() (err error) {
+		*database, err = b.fetcher.GetOracleDatabaseMountedDb(entry)
+		return err
+	})
+	if err != nil {
+		return nil
+	}
+
+	database.Tablespaces = []model.OracleDatabaseTablespace{}
+	database.Schemas = []model.OracleDatabaseSchema{}
+	database.Patches = []model.OracleDatabasePatch{}
+	database.ADDMs = []model.OracleDatabaseAddm{}
+	database.SegmentAdvisors = []model.OracleDatabaseSegmentAdvisor{}
+	database.PSUs = []model.OracleDatabasePSU{}
+	database.PDBs = []model.OracleDatabasePluggableDatabase{}
+	database.Services = []model.OracleDatabaseService{}
+	database.FeatureUsageStats = []model.OracleDatabaseFeatureUsageStat{}
+	database.Partitionings = []model.OracleDatabasePartitioning{}
+
+	disabledSections := b.disabledSections(entry.DBName)
+	if !databaseWideSections {
+		addSections(disabledSections, racDatabaseWideSections)
+	}
+	database.SkippedSections = sortedSections(disabledSections)
+
+	database.Licenses = computeLicenses(database.Edition(), database.CoreFactor(host), host.CPUCores)
+
+	// Parameters are per instance, v$parameter and v$spparameter can be read on mounted databases too
+	if !disabledSections[config.OracleDatabaseSectionParameters] {
+		database.Parameters = b.getOracleDBParameters(entry)
+	} else {
+		database.Parameters = []model.OracleDatabaseParameter{}
+	}
+
+	if !databaseWideSections {
+		database.Backups = []model.OracleDatabaseBackup{}
+		database.BackupJobs = []model.OracleDatabaseBackupJob{}
+		return database
+	}
+
+	// Backups are read from the v$rman views, standbys are often the ones backed up
+	err = b.fetch("backups", entry.DBName, func() (err error) {
+		database.Backups, err = b.fetcher.GetOracleDatabaseBackups(entry)
+		return err
+	})
+	if err != nil {
+		database.Backups = []model.OracleDatabaseBackup{}
+	}
+
+	b.getOracleDBBackupHistory(entry, database)
 
 	return database
 }
 
-func (b *CommonBuilder) getOpenDatabase(entry agentmodel.OratabEntry, hardwareAbstractionTechnology string) *model.OracleDatabase {
-	stringDbVersion := b.fetcher.GetOracleDatabaseDbVersion(entry)
+func (b *CommonBuilder) getOracleDBBackupHistory(entry agentmodel.OratabEntry, database *model.OracleDatabase) {
+	err := b.fetch("backupHistory", entry.DBName, func() (err error) {
+		database.BackupJobs, database.LastBackups, err = b.fetcher.GetOracleDatabaseBackupHistory(entry)
+		return err
+	})
+	if err != nil {
+		database.BackupJobs = []model.OracleDatabaseBackupJob{}
+	}
+}
+
+// getOpenDatabase collects an open database, with only its per-node sections if databaseWideSections is false
+func (b *CommonBuilder) getOpenDatabase(entry agentmodel.OratabEntry, hardwareAbstractionTechnology string, databaseWideSections bool) *model.OracleDatabase {
+	var stringDbVersion string
+	err := b.fetch("dbVersion", entry.DBName, func() (err error) {
+		stringDbVersion, err = b.fetcher.GetOracleDatabaseDbVersion(entry)
+		return err
+	})
+	if err != nil {
+		return nil
+	}
 
-	if b.configuration.Features.OracleDatabase.Forcestats {
-		b.fetcher.RunOracleDatabaseStats(entry)
+	if b.configuration.Features.OracleDatabase.Forcestats && databaseWideSections {
+		b.fetch("stats", entry.DBName, func() error {
+			return b.fetcher.RunOracleDatabaseStats(entry)
+		})
+	}
 
+	var database model.OracleDatabase
+	err = b.fetch("database", entry.DBName, func() (err error) {
+		database, err = b.fetcher.GetOracleDatabaseOpenDb(entry)
+		return err
+	})
+	if err != nil {
+		return nil
 	}
 
-	database := b.fetcher.GetOracleDatabaseOpenDb(entry)
+	if !databaseWideSections {
+		return b.getInstanceOnlyDatabase(entry, &database, stringDbVersion, hardwareAbstractionTechnology)
+	}
+
+	disabledSections := b.disabledSections(entry.DBName)
+	database.SkippedSections = sortedSections(disabledSections)
+
 	var wg sync.WaitGroup
+	dbPool := utils.NewWorkerPool(b.configuration, b.configuration.MaxParallelRequestsPerDatabase)
 
-	utils.RunRoutineInGroup(b.configuration, func() {
-		database.PDBs = []model.OracleDatabasePluggableDatabase{}
+	// The pdbs are listed in the pool too, their sections are queued once they are known
+	pdbsChannel := make(chan []model.OracleDatabasePluggableDatabase, 1)
+	dbPool.RunInGroup(func() {
+		pdbsChannel <- b.getOracleDBPDBs(entry, stringDbVersion)
 	}, &wg)
 
-	utils.RunRoutineInGroup(b.configuration, func() {
-		database.Tablespaces = b.fetcher.GetOracleDatabaseTablespaces(entry)
+	dbPool.RunInGroup(func() {
+		err := b.fetch("tablespaces", entry.DBName, func() (err error) {
+			database.Tablespaces, err = b.fetcher.GetOracleDatabaseTablespaces(entry)
+			return err
+		})
+		if err != nil {
+			database.Tablespaces = []model.OracleDatabaseTablespace{}
+		}
 	}, &wg)
 
-	utils.RunRoutineInGroup(b.configuration, func() {
-		database.Schemas = b.fetcher.GetOracleDatabaseSchemas(entry)
-	}, &wg)
+	if !disabledSections[config.OracleDatabaseSectionSchemas] {
+		dbPool.RunInGroup(func() {
+			err := b.fetch("schemas", entry.DBName, func() (err error) {
+				database.Schemas, err = b.fetcher.GetOracleDatabaseSchemas(entry)
+				return err
+			})
+			if err != nil {
+				database.Schemas = []model.OracleDatabaseSchema{}
+			}
+		}, &wg)
+	} else {
+		database.Schemas = []model.OracleDatabaseSchema{}
+	}
 
-	utils.RunRoutineInGroup(b.configuration, func() {
-		database.Patches = b.fetcher.GetOracleDatabasePatches(entry, stringDbVersion)
+	dbPool.RunInGroup(func() {
+		err := b.fetch("patches", entry.DBName, func() (err error) {
+			database.Patches, err = b.fetcher.GetOracleDatabasePatches(entry, stringDbVersion)
+			return err
+		})
+		if err != nil {
+			database.Patches = []model.OracleDatabasePatch{}
+		}
 	}, &wg)
 
-	utils.RunRoutineInGroup(b.configuration, func() {
-		database.FeatureUsageStats = b.fetcher.GetOracleDatabaseFeatureUsageStat(entry, stringDbVersion)
+	dbPool.RunInGroup(func() {
+		err := b.fetch("featureUsageStats", entry.DBName, func() (err error) {
+			database.FeatureUsageStats, err = b.fetcher.GetOracleDatabaseFeatureUsageStat(entry, stringDbVersion)
+			return err
+		})
+		if err != nil {
+			database.FeatureUsageStats = []model.OracleDatabaseFeatureUsageStat{}
+		}
 	}, &wg)
 
-	utils.RunRoutineInGroup(b.configuration, func() {
-		database.Licenses = b.fetcher.GetOracleDatabaseLicenses(entry, stringDbVersion, hardwareAbstractionTechnology)
+	dbPool.RunInGroup(func() {
+		err := b.fetch("licenses", entry.DBName, func() (err error) {
+			database.Licenses, err = b.fetcher.GetOracleDatabaseLicenses(entry, stringDbVersion, hardwareAbstractionTechnology)
+			return err
+		})
+		if err != nil {
+			database.Licenses = []model.OracleDatabaseLicense{}
+		}
 	}, &wg)
 
-	utils.RunRoutineInGroup(b.configuration, func() {
-		database.ADDMs = b.fetcher.GetOracleDatabaseADDMs(entry)
+	if !disabledSections[config.OracleDatabaseSectionADDMs] {
+		dbPool.RunInGroup(func() {
+			err := b.fetch("addms", entry.DBName, func() (err error) {
+				database.ADDMs, err = b.fetcher.GetOracleDatabaseADDMs(entry)
+				return err
+			})
+			if err != nil {
+				database.ADDMs = []model.OracleDatabaseAddm{}
+			}
+		}, &wg)
+	} else {
+		database.ADDMs = []model.OracleDatabaseAddm{}
+	}
+
+	if !disabledSections[config.OracleDatabaseSectionSegmentAdvisors] {
+		dbPool.RunInGroup(func() {
+			err := b.fetch("segmentAdvisors", entry.DBName, func() (err error) {
+				database.SegmentAdvisors, err = b.fetcher.GetOracleDatabaseSegmentAdvisors(entry)
+				return err
+			})
+			if err != nil {
+				database.SegmentAdvisors = []model.OracleDatabaseSegmentAdvisor{}
+			}
+		}, &wg)
+	} else {
+		database.SegmentAdvisors = []model.OracleDatabaseSegmentAdvisor{}
+	}
+
+	dbPool.RunInGroup(func() {
+		err := b.fetch("psus", entry.DBName, func() (err error) {
+			database.PSUs, err = b.fetcher.GetOracleDatabasePSUs(entry, stringDbVersion)
+			return err
+		})
+		if err != nil {
+			database.PSUs = []model.OracleDatabasePSU{}
+		}
 	}, &wg)
 
-	utils.RunRoutineInGroup(b.configuration, func() {
-		database.SegmentAdvisors = b.fetcher.GetOracleDatabaseSegmentAdvisors(entry)
+	dbPool.RunInGroup(func() {
+		err := b.fetch("backups", entry.DBName, func() (err error) {
+			database.Backups, err = b.fetcher.GetOracleDatabaseBackups(entry)
+			return err
+		})
+		if err != nil {
+			database.Backups = []model.OracleDatabaseBackup{}
+		}
 	}, &wg)
 
-	utils.RunRoutineInGroup(b.configuration, func() {
-		database.PSUs = b.fetcher.GetOracleDatabasePSUs(entry, stringDbVersion)
+	dbPool.RunInGroup(func() {
+		b.getOracleDBBackupHistory(entry, &database)
 	}, &wg)
 
-	utils.RunRoutineInGroup(b.configuration, func() {
-		database.Backups = b.fetcher.GetOracleDatabaseBackups(entry)
+	if !disabledSections[config.OracleDatabaseSectionPartitionings] {
+		dbPool.RunInGroup(func() {
+			err := b.fetch("partitionings", entry.DBName, func() (err error) {
+				database.Partitionings, err = b.fetcher.GetOracleDatabasePartitionings(entry)
+				return err
+			})
+			if err != nil {
+				database.Partitionings = []model.OracleDatabasePartitioning{}
+			}
+		}, &wg)
+	} else {
+		database.Partitionings = []model.OracleDatabasePartitioning{}
+	}
+
+	// Security is left nil if it isn't collected
+	if !disabledSections[config.OracleDatabaseSectionSecurity] {
+		dbPool.RunInGroup(func() {
+			err := b.fetch("security", entry.DBName, func() (err error) {
+				database.Security, err = b.fetcher.GetOracleDatabaseSecurity(entry, stringDbVersion)
+				return err
+			})
+			if err != nil {
+				database.Security = nil
+			}
+		}, &wg)
+	}
+
+	if !disabledSections[config.OracleDatabaseSectionParameters] {
+		dbPool.RunInGroup(func() {
+			database.Parameters = b.getOracleDBParameters(entry)
+		}, &wg)
+	} else {
+		database.Parameters = []model.OracleDatabaseParameter{}
+	}
+
+	dbPool.RunInGroup(func() {
+		err := b.fetch("services", entry.DBName, func() (err error) {
+			database.Services, err = b.fetcher.GetOracleDatabaseServices(entry)
+			return err
+		})
+		if err != nil {
+			database.Services = []model.OracleDatabaseService{}
+		}
 	}, &wg)
 
-	utils.RunRoutineInGroup(b.configuration, func() {
-		database.Partitionings = b.fetcher.GetOracleDatabasePartitionings(entry)
+	var clusterwareServices []agentmodel.ClusterwareService
+	dbPool.RunInGroup(func() {
+		err := b.fetch("clusterwareServices", entry.DBName, func() (err error) {
+			clusterwareServices, err = b.fetcher.GetOracleDatabaseClusterwareServices(entry, database.UniqueName)
+			return err
+		})
+		if err != nil {
+			clusterwareServices = []agentmodel.ClusterwareService{}
+		}
 	}, &wg)
 
+	database.PDBs = <-pdbsChannel
+	for i := range database.PDBs {
+		b.getOracleDBPDBSections(entry, &database.PDBs[i], disabledSections, dbPool, &wg)
+	}
+
 	wg.Wait()
 
-	database.Services = []model.OracleDatabaseService{}
+	b.mergeClusterwareServices(&database, clusterwareServices)
 
 	return &database
 }
@@ -28,8 +28,20 @@ func (b *CommonBuilder) getOracleExadataFeature() *model.OracleExadataFeature {
 }
 
 func (b *CommonBuilder) getOracleExadataComponents() []model.OracleExadataComponent {
-	exadataDevices := b.fetcher.GetOracleExadataComponents()
-	exadataCellDisks := b.fetcher.GetOracleExadataCellDisks()
+	var exadataDevices []model.OracleExadataComponent
+	err := b.fetch("exadataComponents", "", func() (err error) {
+		exadataDevices, err = b.fetcher.GetOracleExadataComponents()
+		return err
+	})
+	if err != nil {
+		return []model.OracleExadataComponent{}
+	}
+
+	var exadataCellDisks map[agentmodel.StorageServerName][]model.OracleExadataCellDisk
+	b.fetch("exadataCellDisks", "", func() (err error) {
+		exadataCellDisks, err = b.fetcher.GetOracleExadataCellDisks()
+		return err
+	})
 
 	for i := range exadataDevices {
 		cellDisks := exadataCellDisks[agentmodel.StorageServerName(exadataDevices[i].Hostname)]
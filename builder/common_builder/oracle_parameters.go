@@ -0,0 +1,72 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"github.com/ercole-io/ercole-agent-rhel5/agentmodel"
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+// getOracleDBParameters returns the initialization parameters of the local instance of entry
+// selected by the configuration
+func (b *CommonBuilder) getOracleDBParameters(entry agentmodel.OratabEntry) []model.OracleDatabaseParameter {
+	var parameters []model.OracleDatabaseParameter
+	err := b.fetch("parameters", entry.DBName, func() (err error) {
+		parameters, err = b.fetcher.GetOracleDatabaseParameters(entry)
+		return err
+	})
+	if err != nil {
+		return []model.OracleDatabaseParameter{}
+	}
+
+	return b.selectParameters(parameters)
+}
+
+// getOracleDBPDBParameters returns the initialization parameters of pdb selected by the configuration
+func (b *CommonBuilder) getOracleDBPDBParameters(entry agentmodel.OratabEntry, pdb string) []model.OracleDatabaseParameter {
+	var parameters []model.OracleDatabaseParameter
+	err := b.fetch("pdbParameters", entry.DBName+"/"+pdb, func() (err error) {
+		parameters, err = b.fetcher.GetOracleDatabasePDBParameters(entry, pdb)
+		return err
+	})
+	if err != nil {
+		return []model.OracleDatabaseParameter{}
+	}
+
+	return b.selectParameters(parameters)
+}
+
+// selectParameters keeps the parameters matching the configured patterns or, without patterns,
+// the ones not left to their default value
+func (b *CommonBuilder) selectParameters(parameters []model.OracleDatabaseParameter) []model.OracleDatabaseParameter {
+	patterns := b.configuration.Features.OracleDatabase.Parameters
+
+	selected := make([]model.OracleDatabaseParameter, 0, len(parameters))
+	for _, parameter := range parameters {
+		if len(patterns) > 0 {
+			if matchesAny(patterns, parameter.Name) {
+				selected = append(selected, parameter)
+			}
+			continue
+		}
+
+		if !parameter.IsDefault || parameter.IsModified != "FALSE" || parameter.SPFileValue != nil {
+			selected = append(selected, parameter)
+		}
+	}
+
+	return selected
+}
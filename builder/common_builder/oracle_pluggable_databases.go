@@ -0,0 +1,110 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ercole-io/ercole-agent-rhel5/agentmodel"
+	"github.com/ercole-io/ercole-agent-rhel5/config"
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+	"github.com/ercole-io/ercole-agent-rhel5/utils"
+)
+
+// getOracleDBPDBs returns the pluggable databases of entry, none if it isn't a container database
+func (b *CommonBuilder) getOracleDBPDBs(entry agentmodel.OratabEntry, dbVersion string) []model.OracleDatabasePluggableDatabase {
+	// Multitenant architecture was introduced in 12c
+	if version, err := strconv.Atoi(strings.TrimSpace(dbVersion)); err != nil || version < 12 {
+		return []model.OracleDatabasePluggableDatabase{}
+	}
+
+	var isCDB bool
+	err := b.fetch("checkPDB", entry.DBName, func() (err error) {
+		isCDB, err = b.fetcher.GetOracleDatabaseCheckPDB(entry)
+		return err
+	})
+	if err != nil || !isCDB {
+		return []model.OracleDatabasePluggableDatabase{}
+	}
+
+	var pdbs []model.OracleDatabasePluggableDatabase
+	err = b.fetch("pdbs", entry.DBName, func() (err error) {
+		pdbs, err = b.fetcher.GetOracleDatabasePDBs(entry)
+		return err
+	})
+	if err != nil {
+		return []model.OracleDatabasePluggableDatabase{}
+	}
+
+	return pdbs
+}
+
+// getOracleDBPDBSections collects tablespaces, schemas, services and parameters of pdb running the fetchers in dbPool.
+// They are left empty if pdb isn't open or they are disabled for its database
+func (b *CommonBuilder) getOracleDBPDBSections(entry agentmodel.OratabEntry, pdb *model.OracleDatabasePluggableDatabase,
+	disabledSections map[string]bool, dbPool *utils.WorkerPool, wg *sync.WaitGroup) {
+	pdb.Tablespaces = []model.OracleDatabaseTablespace{}
+	pdb.Schemas = []model.OracleDatabaseSchema{}
+	pdb.Services = []model.OracleDatabaseService{}
+	pdb.Parameters = []model.OracleDatabaseParameter{}
+
+	if pdb.Status != "READ WRITE" && pdb.Status != "READ ONLY" {
+		b.log.Debugf("PDB [%s] of database [%s] is [%s], sections skipped", pdb.Name, entry.DBName, pdb.Status)
+		return
+	}
+
+	dbName := entry.DBName + "/" + pdb.Name
+
+	dbPool.RunInGroup(func() {
+		err := b.fetch("pdbTablespaces", dbName, func() (err error) {
+			pdb.Tablespaces, err = b.fetcher.GetOracleDatabasePDBTablespaces(entry, pdb.Name)
+			return err
+		})
+		if err != nil {
+			pdb.Tablespaces = []model.OracleDatabaseTablespace{}
+		}
+	}, wg)
+
+	if !disabledSections[config.OracleDatabaseSectionSchemas] {
+		dbPool.RunInGroup(func() {
+			err := b.fetch("pdbSchemas", dbName, func() (err error) {
+				pdb.Schemas, err = b.fetcher.GetOracleDatabasePDBSchemas(entry, pdb.Name)
+				return err
+			})
+			if err != nil {
+				pdb.Schemas = []model.OracleDatabaseSchema{}
+			}
+		}, wg)
+	}
+
+	dbPool.RunInGroup(func() {
+		err := b.fetch("pdbServices", dbName, func() (err error) {
+			pdb.Services, err = b.fetcher.GetOracleDatabasePDBServices(entry, pdb.Name)
+			return err
+		})
+		if err != nil {
+			pdb.Services = []model.OracleDatabaseService{}
+		}
+	}, wg)
+
+	if !disabledSections[config.OracleDatabaseSectionParameters] {
+		dbPool.RunInGroup(func() {
+			pdb.Parameters = b.getOracleDBPDBParameters(entry, pdb.Name)
+		}, wg)
+	}
+}
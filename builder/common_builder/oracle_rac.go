@@ -0,0 +1,138 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"github.com/ercole-io/ercole-agent-rhel5/agentmodel"
+	"github.com/ercole-io/ercole-agent-rhel5/config"
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+// racDatabaseWideSections are the sections with the same content on every instance of a cluster database
+var racDatabaseWideSections = []string{
+	"addms",
+	"backupHistory",
+	"backups",
+	"dataguard",
+	"featureUsageStats",
+	"grantDba",
+	"partitionings",
+	"patches",
+	"pdbs",
+	"psus",
+	"schemas",
+	"security",
+	"segmentAdvisors",
+	"services",
+	"stats",
+	"tablespaces",
+}
+
+// getOracleDBInstances returns whether the database is a cluster database and its running instances.
+// On errors the database is handled as a single instance one
+func (b *CommonBuilder) getOracleDBInstances(entry agentmodel.OratabEntry) (bool, []model.OracleDatabaseInstance) {
+	var clusterDatabase bool
+	var instances []model.OracleDatabaseInstance
+
+	err := b.fetch("instances", entry.DBName, func() (err error) {
+		clusterDatabase, instances, err = b.fetcher.GetOracleDatabaseInstances(entry)
+		return err
+	})
+	if err != nil {
+		return false, []model.OracleDatabaseInstance{}
+	}
+
+	return clusterDatabase, instances
+}
+
+// collectsDatabaseWideSections tells whether this node collects the database-wide sections of the database:
+// with RACCollectOnLowestInstance only the node of the running instance with the lowest number does
+func (b *CommonBuilder) collectsDatabaseWideSections(entry agentmodel.OratabEntry, clusterDatabase bool, instances []model.OracleDatabaseInstance) bool {
+	if !clusterDatabase || !b.configuration.Features.OracleDatabase.RACCollectOnLowestInstance {
+		return true
+	}
+
+	var lowest, local *model.OracleDatabaseInstance
+	for i := range instances {
+		if lowest == nil || instances[i].Number < lowest.Number {
+			lowest = &instances[i]
+		}
+
+		if instances[i].Local {
+			local = &instances[i]
+		}
+	}
+
+	if local == nil || local == lowest {
+		return true
+	}
+
+	b.log.Infof("Database [%s] is collected by instance [%s] on [%s], only the local instance is reported",
+		entry.DBName, lowest.Name, lowest.Host)
+
+	return false
+}
+
+// getInstanceOnlyDatabase completes database, the local instance of a cluster database collected by another node,
+// with the per-node sections only
+func (b *CommonBuilder) getInstanceOnlyDatabase(entry agentmodel.OratabEntry, database *model.OracleDatabase,
+	dbVersion, hardwareAbstractionTechnology string) *model.OracleDatabase {
+	database.Tablespaces = []model.OracleDatabaseTablespace{}
+	database.Schemas = []model.OracleDatabaseSchema{}
+	database.Patches = []model.OracleDatabasePatch{}
+	database.ADDMs = []model.OracleDatabaseAddm{}
+	database.SegmentAdvisors = []model.OracleDatabaseSegmentAdvisor{}
+	database.PSUs = []model.OracleDatabasePSU{}
+	database.Backups = []model.OracleDatabaseBackup{}
+	database.BackupJobs = []model.OracleDatabaseBackupJob{}
+	database.PDBs = []model.OracleDatabasePluggableDatabase{}
+	database.Services = []model.OracleDatabaseService{}
+	database.FeatureUsageStats = []model.OracleDatabaseFeatureUsageStat{}
+	database.Partitionings = []model.OracleDatabasePartitioning{}
+	database.GrantDba = []model.OracleGrantDba{}
+
+	disabledSections := b.disabledSections(entry.DBName)
+	addSections(disabledSections, racDatabaseWideSections)
+	database.SkippedSections = sortedSections(disabledSections)
+
+	// Parameters, like licenses, are per instance
+	if !disabledSections[config.OracleDatabaseSectionParameters] {
+		database.Parameters = b.getOracleDBParameters(entry)
+	} else {
+		database.Parameters = []model.OracleDatabaseParameter{}
+	}
+
+	// Licenses are computed on the cores of the local host, every node needs its own
+	err := b.fetch("licenses", entry.DBName, func() (err error) {
+		database.Licenses, err = b.fetcher.GetOracleDatabaseLicenses(entry, dbVersion, hardwareAbstractionTechnology)
+		return err
+	})
+	if err != nil {
+		database.Licenses = []model.OracleDatabaseLicense{}
+	}
+
+	return database
+}
+
+func hasSkippedSection(database *model.OracleDatabase, section string) bool {
+	for _, skipped := range database.SkippedSections {
+		if skipped == section {
+			return true
+		}
+	}
+
+	return false
+}
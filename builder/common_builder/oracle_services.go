@@ -0,0 +1,73 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"strings"
+
+	"github.com/ercole-io/ercole-agent-rhel5/agentmodel"
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+// mergeClusterwareServices completes the services of database and of its pdbs with their Grid Infrastructure configuration.
+// Services registered only in Grid Infrastructure are added as not running. The services of pdbs which weren't
+// collected, e.g. closed or filtered ones, are skipped
+func (b *CommonBuilder) mergeClusterwareServices(database *model.OracleDatabase, clusterwareServices []agentmodel.ClusterwareService) {
+	for _, clusterwareService := range clusterwareServices {
+		services := &database.Services
+
+		if clusterwareService.PDB != "" {
+			services = nil
+			for i := range database.PDBs {
+				if strings.EqualFold(database.PDBs[i].Name, clusterwareService.PDB) {
+					services = &database.PDBs[i].Services
+					break
+				}
+			}
+
+			if services == nil {
+				b.log.Debugf("Service [%s] of database [%s] skipped: PDB [%s] wasn't collected",
+					clusterwareService.Name, database.Name, clusterwareService.PDB)
+				continue
+			}
+		}
+
+		*services = mergeClusterwareService(*services, clusterwareService)
+	}
+}
+
+func mergeClusterwareService(services []model.OracleDatabaseService, clusterwareService agentmodel.ClusterwareService) []model.OracleDatabaseService {
+	for i := range services {
+		if strings.EqualFold(services[i].Name, clusterwareService.Name) {
+			setClusterwareConfiguration(&services[i], clusterwareService)
+			return services
+		}
+	}
+
+	service := model.OracleDatabaseService{Name: clusterwareService.Name}
+	setClusterwareConfiguration(&service, clusterwareService)
+
+	return append(services, service)
+}
+
+func setClusterwareConfiguration(service *model.OracleDatabaseService, clusterwareService agentmodel.ClusterwareService) {
+	if clusterwareService.FailoverType != "" {
+		service.FailoverType = clusterwareService.FailoverType
+	}
+
+	service.PreferredInstances = clusterwareService.PreferredInstances
+	service.AvailableInstances = clusterwareService.AvailableInstances
+}
@@ -17,7 +17,6 @@ package common
 
 import (
 	"github.com/ercole-io/ercole-agent-rhel5/model"
-	"github.com/ercole-io/ercole-agent-rhel5/utils"
 )
 
 func (b *CommonBuilder) getClustersInfos() []model.ClusterInfo {
@@ -27,12 +26,26 @@ func (b *CommonBuilder) getClustersInfos() []model.ClusterInfo {
 	vmsChan := make(chan map[string][]model.VMInfo, countHypervisors)
 
 	for _, hv := range b.configuration.Features.Virtualization.Hypervisors {
-		utils.RunRoutine(b.configuration, func() {
-			clustersChan <- b.fetcher.GetClusters(hv)
+		hv := hv
+
+		b.pool.Run(func() {
+			var clusters []model.ClusterInfo
+			b.fetch("clusters", "", func() (err error) {
+				clusters, err = b.fetcher.GetClusters(hv)
+				return err
+			})
+
+			clustersChan <- clusters
 		})
 
-		utils.RunRoutine(b.configuration, func() {
-			vmsChan <- b.fetcher.GetVirtualMachines(hv)
+		b.pool.Run(func() {
+			var vms map[string][]model.VMInfo
+			b.fetch("vms", "", func() (err error) {
+				vms, err = b.fetcher.GetVirtualMachines(hv)
+				return err
+			})
+
+			vmsChan <- vms
 		})
 	}
 
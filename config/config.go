@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
 
 	"github.com/ercole-io/ercole-agent-rhel5/logger"
@@ -26,19 +27,42 @@ import (
 
 // Configuration holds the agent configuration options
 type Configuration struct {
-	Hostname               string
-	Environment            string
-	Location               string
-	DataserviceURL         string
-	AgentUser              string
-	AgentPassword          string
-	EnableServerValidation bool
-	ForcePwshVersion       string
-	Period                 uint
-	Verbose                bool
-	ParallelizeRequests    bool
-	LogDirectory           string
-	Features               Features
+	Hostname                       string
+	Environment                    string
+	Location                       string
+	DataserviceURL                 string
+	AgentUser                      string
+	AgentPassword                  string
+	EnableServerValidation         bool
+	ForcePwshVersion               string
+	Period                         uint
+	Verbose                        bool
+	ParallelizeRequests            bool
+	MaxParallelRequests            uint
+	MaxParallelRequestsPerDatabase uint
+	LogDirectory                   string
+	RunDirectory                   string
+	FetcherTimeout                 uint
+	FetcherTimeouts                map[string]uint
+	ShutdownGracePeriod            uint
+	AllowUnsignedManifest          bool
+	FetcherEnvironment             FetcherEnvironment
+	LocalServer                    LocalServer
+	Features                       Features
+}
+
+// FetcherEnvironment holds the environment of the fetchers, which don't inherit the agent's one
+type FetcherEnvironment struct {
+	Path             string
+	Lang             string
+	NLSLang          string
+	AllowedVariables []string
+}
+
+// LocalServer holds the params of the local http listener, used for monitoring the agent
+type LocalServer struct {
+	Enabled bool
+	Address string
 }
 
 // Features holds features params
@@ -46,6 +70,7 @@ type Features struct {
 	OracleDatabase     OracleDatabaseFeature
 	Virtualization     VirtualizationFeature
 	OracleExadata      OracleExadataFeature
+	OracleASM          OracleASMFeature
 	MicrosoftSQLServer MicrosoftSQLServerFeature
 }
 
@@ -56,6 +81,48 @@ type OracleDatabaseFeature struct {
 	Oratab      string
 	AWR         int
 	Forcestats  bool
+	// BackupHistoryDays is how many days of RMAN jobs are collected
+	BackupHistoryDays uint
+	// DiscoverRunningDatabases collects also the running instances missing from oratab,
+	// resolving their ORACLE_HOME from the pmon process
+	DiscoverRunningDatabases bool
+	// DiscoverClusterwareDatabases collects also the instances of the databases registered in Grid Infrastructure
+	DiscoverClusterwareDatabases bool
+	// RACCollectOnLowestInstance collects the database-wide sections of a cluster database
+	// only on the node of its running instance with the lowest number
+	RACCollectOnLowestInstance bool
+	// Include selects the databases to collect, all if empty. Exclude wins over it
+	Include OracleDatabaseFilter
+	Exclude OracleDatabaseFilter
+	// DisabledSections maps database name patterns to the sections not collected for them
+	DisabledSections map[string][]string
+	// Parameters are the patterns of the initialization parameters collected, if empty the non-default ones
+	Parameters []string
+}
+
+// OracleDatabaseFilter holds patterns, in path.Match syntax, matched against the oratab entries
+type OracleDatabaseFilter struct {
+	DBNames     []string
+	OracleHomes []string
+}
+
+// Oracle database sections that can be disabled by OracleDatabaseFeature.DisabledSections
+const (
+	OracleDatabaseSectionADDMs           = "addms"
+	OracleDatabaseSectionSegmentAdvisors = "segmentAdvisors"
+	OracleDatabaseSectionPartitionings   = "partitionings"
+	OracleDatabaseSectionSchemas         = "schemas"
+	OracleDatabaseSectionParameters      = "parameters"
+	OracleDatabaseSectionSecurity        = "security"
+)
+
+var oracleDatabaseDisableableSections = map[string]bool{
+	OracleDatabaseSectionADDMs:           true,
+	OracleDatabaseSectionSegmentAdvisors: true,
+	OracleDatabaseSectionPartitionings:   true,
+	OracleDatabaseSectionSchemas:         true,
+	OracleDatabaseSectionParameters:      true,
+	OracleDatabaseSectionSecurity:        true,
 }
 
 // VirtualizationFeature holds virtualization feature params
@@ -81,6 +148,13 @@ type OracleExadataFeature struct {
 	FetcherUser string
 }
 
+// OracleASMFeature holds oracle asm feature params.
+// The ASM instance is looked up in the oratab of the OracleDatabase feature
+type OracleASMFeature struct {
+	Enabled     bool
+	FetcherUser string
+}
+
 // MicrosoftSQLServerFeature holds microsoft sql server feature params
 type MicrosoftSQLServerFeature struct {
 	Enabled     bool
@@ -117,6 +191,30 @@ func ReadConfig(log logger.Logger) Configuration {
 	return conf
 }
 
+// Masked return a copy of the configuration with the secrets replaced by a placeholder
+func (configuration Configuration) Masked() Configuration {
+	const mask = "********"
+
+	masked := configuration
+	if masked.AgentPassword != "" {
+		masked.AgentPassword = mask
+	}
+
+	masked.Features.Virtualization.Hypervisors = make([]Hypervisor, len(configuration.Features.Virtualization.Hypervisors))
+	for i, hv := range configuration.Features.Virtualization.Hypervisors {
+		if hv.Password != "" {
+			hv.Password = mask
+		}
+		if hv.OvmUserKey != "" {
+			hv.OvmUserKey = mask
+		}
+
+		masked.Features.Virtualization.Hypervisors[i] = hv
+	}
+
+	return masked
+}
+
 func exists(name string) bool {
 	_, err := os.Stat(name)
 
@@ -126,10 +224,28 @@ func exists(name string) bool {
 func checkConfiguration(log logger.Logger, config *Configuration) {
 	checkPeriod(log, config)
 	checkLogDirectory(log, config)
+	checkRunDirectory(log, config)
+	checkFetcherTimeout(log, config)
+	checkShutdownGracePeriod(log, config)
+	checkFetcherEnvironment(config)
+	checkMaxParallelRequests(log, config)
+	checkLocalServer(log, config)
 
 	if config.Features.OracleDatabase.Oratab == "" {
 		config.Features.OracleDatabase.Oratab = "/etc/oratab"
 	}
+
+	checkOracleDatabaseFilters(log, config)
+	checkBackupHistoryDays(log, config)
+}
+
+func checkBackupHistoryDays(log logger.Logger, config *Configuration) {
+	if config.Features.OracleDatabase.BackupHistoryDays == 0 {
+		defaultBackupHistoryDays := uint(7)
+		log.Warnf("BackupHistoryDays has invalid value [%d], set to default value [%d]",
+			config.Features.OracleDatabase.BackupHistoryDays, defaultBackupHistoryDays)
+		config.Features.OracleDatabase.BackupHistoryDays = defaultBackupHistoryDays
+	}
 }
 
 func checkPeriod(log logger.Logger, config *Configuration) {
@@ -140,6 +256,122 @@ func checkPeriod(log logger.Logger, config *Configuration) {
 	}
 }
 
+func checkFetcherTimeout(log logger.Logger, config *Configuration) {
+	if config.FetcherTimeout == 0 {
+		defaultFetcherTimeout := uint(1800)
+		log.Warnf("FetcherTimeout has invalid value [%d], set to default value [%d]", config.FetcherTimeout, defaultFetcherTimeout)
+		config.FetcherTimeout = defaultFetcherTimeout
+	}
+}
+
+func checkShutdownGracePeriod(log logger.Logger, config *Configuration) {
+	if config.ShutdownGracePeriod == 0 {
+		defaultShutdownGracePeriod := uint(30)
+		log.Warnf("ShutdownGracePeriod has invalid value [%d], set to default value [%d]", config.ShutdownGracePeriod, defaultShutdownGracePeriod)
+		config.ShutdownGracePeriod = defaultShutdownGracePeriod
+	}
+}
+
+func checkFetcherEnvironment(config *Configuration) {
+	if config.FetcherEnvironment.Path == "" {
+		config.FetcherEnvironment.Path = "/usr/local/bin:/bin:/usr/bin:/usr/local/sbin:/usr/sbin:/sbin"
+	}
+
+	if config.FetcherEnvironment.Lang == "" {
+		config.FetcherEnvironment.Lang = "C"
+	}
+
+	if config.FetcherEnvironment.NLSLang == "" {
+		config.FetcherEnvironment.NLSLang = "AMERICAN_AMERICA.AL32UTF8"
+	}
+}
+
+// checkMaxParallelRequests sets the default limits: MaxParallelRequests bounds the fetchers running at the same time
+// on the host, MaxParallelRequestsPerDatabase the sections of a single database collected at the same time
+func checkMaxParallelRequests(log logger.Logger, config *Configuration) {
+	if !config.ParallelizeRequests {
+		return
+	}
+
+	if config.MaxParallelRequests == 0 {
+		defaultMaxParallelRequests := uint(4)
+		log.Warnf("MaxParallelRequests has invalid value [%d], set to default value [%d]", config.MaxParallelRequests, defaultMaxParallelRequests)
+		config.MaxParallelRequests = defaultMaxParallelRequests
+	}
+
+	if config.MaxParallelRequestsPerDatabase == 0 {
+		defaultMaxParallelRequestsPerDatabase := uint(4)
+		log.Warnf("MaxParallelRequestsPerDatabase has invalid value [%d], set to default value [%d]", config.MaxParallelRequestsPerDatabase, defaultMaxParallelRequestsPerDatabase)
+		config.MaxParallelRequestsPerDatabase = defaultMaxParallelRequestsPerDatabase
+	}
+}
+
+func checkLocalServer(log logger.Logger, config *Configuration) {
+	if !config.LocalServer.Enabled {
+		return
+	}
+
+	if config.LocalServer.Address == "" {
+		defaultAddress := "127.0.0.1:9797"
+		log.Warnf("LocalServer.Address has invalid value [%s], set to default value [%s]", config.LocalServer.Address, defaultAddress)
+		config.LocalServer.Address = defaultAddress
+	}
+}
+
+func checkOracleDatabaseFilters(log logger.Logger, config *Configuration) {
+	oracle := &config.Features.OracleDatabase
+
+	oracle.Include.DBNames = validPatterns(log, "Include.DBNames", oracle.Include.DBNames)
+	oracle.Include.OracleHomes = validPatterns(log, "Include.OracleHomes", oracle.Include.OracleHomes)
+	oracle.Exclude.DBNames = validPatterns(log, "Exclude.DBNames", oracle.Exclude.DBNames)
+	oracle.Exclude.OracleHomes = validPatterns(log, "Exclude.OracleHomes", oracle.Exclude.OracleHomes)
+	oracle.Parameters = validPatterns(log, "Parameters", oracle.Parameters)
+
+	for pattern, sections := range oracle.DisabledSections {
+		if _, err := path.Match(pattern, ""); err != nil {
+			log.Warnf("DisabledSections pattern [%s] is invalid, ignored: %v", pattern, err)
+			delete(oracle.DisabledSections, pattern)
+			continue
+		}
+
+		valid := make([]string, 0, len(sections))
+		for _, section := range sections {
+			if !oracleDatabaseDisableableSections[section] {
+				log.Warnf("DisabledSections of [%s] has invalid section [%s], ignored", pattern, section)
+				continue
+			}
+
+			valid = append(valid, section)
+		}
+		oracle.DisabledSections[pattern] = valid
+	}
+}
+
+func validPatterns(log logger.Logger, name string, patterns []string) []string {
+	valid := make([]string, 0, len(patterns))
+
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			log.Warnf("%s pattern [%s] is invalid, ignored: %v", name, pattern, err)
+			continue
+		}
+
+		valid = append(valid, pattern)
+	}
+
+	return valid
+}
+
+func checkRunDirectory(log logger.Logger, config *Configuration) {
+	if config.RunDirectory == "" {
+		config.RunDirectory = filepath.Join(GetBaseDir(), "run")
+	}
+
+	if err := os.MkdirAll(config.RunDirectory, 0755); err != nil {
+		log.Fatal("RunDirectory is not valid: ", err)
+	}
+}
+
 func checkLogDirectory(log logger.Logger, config *Configuration) {
 	path := config.LogDirectory
 	if path == "" {
@@ -0,0 +1,49 @@
+#!/bin/sh
+
+# Copyright (c) 2023 Sorint.lab S.p.A.
+#
+# This program is free software: you can redistribute it and/or modify
+# it under the terms of the GNU General Public License as published by
+# the Free Software Foundation, either version 3 of the License, or
+# (at your option) any later version.
+#
+# This program is distributed in the hope that it will be useful,
+# but WITHOUT ANY WARRANTY; without even the implied warranty of
+# MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+# GNU General Public License for more details.
+#
+# You should have received a copy of the GNU General Public License
+# along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+ORATAB=$1
+
+if [ -z "$ORATAB" ]; then
+  >&2 echo "Missing ORATAB parameter"
+  exit 1
+fi
+
+if [ ! -r "$ORATAB" ]; then
+  >&2 echo "The file $ORATAB doesn't exist or isn't readable"
+  exit 1
+fi
+
+# The first +ASM instance of oratab, nothing is printed if there's none
+ASM_ENTRY=$(sed 's/#.*$//' $ORATAB | grep "^\s*+ASM[0-9]*\s*:" | head -n 1)
+if [ -z "$ASM_ENTRY" ]; then
+  exit 0
+fi
+
+SID=$(echo "$ASM_ENTRY" | cut -d ':' -f 1 | tr -d ' \t')
+HOME=$(echo "$ASM_ENTRY" | cut -d ':' -f 2 | tr -d ' \t')
+
+LINUX_FETCHERS_DIR=$(dirname "$0")
+FETCHERS_DIR="$(dirname "$LINUX_FETCHERS_DIR")"
+ERCOLE_HOME="$(dirname "$FETCHERS_DIR")"
+
+export ORAENV_ASK=NO 
+export ORACLE_SID=$SID
+export ORACLE_HOME=$HOME
+export PATH=$HOME/bin:$PATH
+
+echo "INSTANCE|||$SID"
+sqlplus -S "/ AS SYSDBA" @${ERCOLE_HOME}/sql/asm.sql
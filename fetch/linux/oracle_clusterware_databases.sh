@@ -0,0 +1,33 @@
+#!/bin/sh
+
+# Copyright (c) 2023 Sorint.lab S.p.A.
+#
+# This program is free software: you can redistribute it and/or modify
+# it under the terms of the GNU General Public License as published by
+# the Free Software Foundation, either version 3 of the License, or
+# (at your option) any later version.
+#
+# This program is distributed in the hope that it will be useful,
+# but WITHOUT ANY WARRANTY; without even the implied warranty of
+# MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+# GNU General Public License for more details.
+#
+# You should have received a copy of the GNU General Public License
+# along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+# Print the attributes of the databases registered in Grid Infrastructure, RAC or Oracle Restart,
+# nothing if it isn't installed
+
+OLR_LOC=/etc/oracle/olr.loc
+
+if [ ! -r "$OLR_LOC" ]; then
+  exit 0
+fi
+
+CRS_HOME=$(sed -n 's/^crs_home=//p' "$OLR_LOC")
+if [ -z "$CRS_HOME" ] || [ ! -x "$CRS_HOME/bin/crsctl" ]; then
+  >&2 echo "Can't find crsctl of the Grid Infrastructure home [$CRS_HOME] from $OLR_LOC"
+  exit 1
+fi
+
+"$CRS_HOME/bin/crsctl" stat res -p -w "TYPE = ora.database.type"
@@ -0,0 +1,28 @@
+#!/bin/sh
+
+# Copyright (c) 2023 Sorint.lab S.p.A.
+#
+# This program is free software: you can redistribute it and/or modify
+# it under the terms of the GNU General Public License as published by
+# the Free Software Foundation, either version 3 of the License, or
+# (at your option) any later version.
+#
+# This program is distributed in the hope that it will be useful,
+# but WITHOUT ANY WARRANTY; without even the implied warranty of
+# MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+# GNU General Public License for more details.
+#
+# You should have received a copy of the GNU General Public License
+# along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+# Print SID|||ORACLE_HOME of the running instances, the home is resolved from
+# the executable of their pmon process or, if it can't be read, from its environment
+
+ps -eo pid,args | awk '$2 ~ /^ora_pmon_./ { sub(/^ora_pmon_/, "", $2); print $1, $2 }' | while read PID SID; do
+  HOME=$(readlink "/proc/$PID/exe" 2>/dev/null | sed -n -e 's/ (deleted)$//' -e 's#/bin/oracle$##p')
+  if [ -z "$HOME" ]; then
+    HOME=$(tr '\0' '\n' < "/proc/$PID/environ" 2>/dev/null | sed -n 's/^ORACLE_HOME=//p' | head -n 1)
+  fi
+
+  echo "$SID|||$HOME"
+done
@@ -0,0 +1,46 @@
+#!/bin/sh
+
+# Copyright (c) 2023 Sorint.lab S.p.A.
+#
+# This program is free software: you can redistribute it and/or modify
+# it under the terms of the GNU General Public License as published by
+# the Free Software Foundation, either version 3 of the License, or
+# (at your option) any later version.
+#
+# This program is distributed in the hope that it will be useful,
+# but WITHOUT ANY WARRANTY; without even the implied warranty of
+# MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+# GNU General Public License for more details.
+#
+# You should have received a copy of the GNU General Public License
+# along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+SID=$1
+HOME=$2
+DB_UNIQUE_NAME=$3
+
+if [ -z "$SID" ]; then
+  >&2 echo "Missing SID parameter"
+  exit 1
+fi
+if [ -z "$HOME" ]; then
+  >&2 echo "Missing ORACLE_HOME parameter"
+  exit 1
+fi
+if [ -z "$DB_UNIQUE_NAME" ]; then
+  >&2 echo "Missing DB_UNIQUE_NAME parameter"
+  exit 1
+fi
+
+# Services are registered only when Grid Infrastructure, RAC or Oracle Restart, is running
+CHECK_GRID_INFRASTRUCTURE=$(ps -eo cmd | grep -v grep | grep "/ohasd.bin\b" | wc -l)
+if [ $CHECK_GRID_INFRASTRUCTURE -eq 0 ]; then
+  exit 0
+fi
+
+export ORAENV_ASK=NO 
+export ORACLE_SID=$SID
+export ORACLE_HOME=$HOME
+export PATH=$HOME/bin:$PATH
+
+srvctl config service -d "$DB_UNIQUE_NAME"
@@ -18,33 +18,120 @@
 package fetcher
 
 import (
+	"fmt"
 	"os/exec"
 	"syscall"
+	"time"
 
 	"github.com/ercole-io/ercole-agent-rhel5/logger"
 )
 
-// runCommandAs utility
-func runCommandAs(log logger.Logger, u *User, commandName string, args ...string) (stdout, stderr []byte, exitCode int, err error) {
+// killWaitTimeout is how long to wait for a killed command to release its output
+const killWaitTimeout = 10 * time.Second
+
+// runCommandAs run the command as the user u with the environment env,
+// killing it when timeout expires or cancel is closed
+func runCommandAs(log logger.Logger, u *User, env []string, timeout time.Duration, cancel <-chan struct{}, commandName string, args ...string) *FetchResult {
+	result := &FetchResult{Command: commandName, ExitCode: -1}
+
+	select {
+	case <-cancel:
+		result.Canceled = true
+		result.Err = &CanceledError{Command: commandName}
+
+		return result
+	default:
+	}
+
 	cmd := exec.Command(commandName, args...)
+	cmd.Env = env
+
+	// Run the command in its own process group, so that on timeout or cancellation
+	// all its children (sqlplus, ...) can be killed with it
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 
 	if u != nil {
 		log.Debugf("runCommand [%v] with user [%v]", commandName, u)
 
-		cmd.SysProcAttr = &syscall.SysProcAttr{}
-		if err != nil {
-			log.Errorf("Can't set process attributes at command [%v]", commandName)
-			return nil, nil, -1, err
+		cmd.SysProcAttr.Credential = &syscall.Credential{Uid: u.UID, Gid: u.GID, Groups: u.Groups}
+	}
+
+	stdout := newCappedBuffer(maxStdoutSize)
+	stderr := newCappedBuffer(maxStderrSize)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	start := time.Now()
+	defer func() {
+		result.Duration = time.Since(start)
+	}()
+
+	if result.Err = cmd.Start(); result.Err != nil {
+		return result
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		deadline = timer.C
+	}
+
+	select {
+	case result.Err = <-done:
+	case <-deadline:
+		log.Errorf("Command [%v] timed out after %v, killing its process group", commandName, timeout)
+		killProcessGroup(log, cmd, done)
+
+		result.TimedOut = true
+		result.Err = &TimeoutError{Command: commandName, Timeout: timeout}
+
+		return result
+	case <-cancel:
+		log.Warnf("Command [%v] canceled, killing its process group", commandName)
+		killProcessGroup(log, cmd, done)
+
+		result.Canceled = true
+		result.Err = &CanceledError{Command: commandName}
+
+		return result
+	}
+
+	result.Stdout = stdout.Bytes()
+	result.Stderr = stderr.Bytes()
+	result.StdoutTruncated = stdout.truncated
+	result.StderrTruncated = stderr.truncated
+
+	if status, ok := cmd.ProcessState.Sys().(syscall.WaitStatus); ok {
+		if status.Signaled() {
+			result.Signal = status.Signal().String()
+		} else {
+			result.ExitCode = status.ExitStatus()
 		}
+	}
 
-		cmd.SysProcAttr.Credential = &syscall.Credential{Uid: u.UID, Gid: u.GID}
+	if result.Err == nil && result.StdoutTruncated {
+		result.Err = fmt.Errorf("Command [%s] stdout exceeded %d bytes", commandName, maxStdoutSize)
 	}
 
-	stdout, err = cmd.Output()
+	return result
+}
 
-	if err != nil {
-		exitCode = -1
+// killProcessGroup kill the process group of cmd and wait for cmd to terminate
+func killProcessGroup(log logger.Logger, cmd *exec.Cmd, done <-chan error) {
+	if errKill := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL); errKill != nil {
+		log.Errorf("Can't kill process group of command [%v]: [%v]", cmd.Path, errKill)
 	}
 
-	return
+	select {
+	case <-done:
+	case <-time.After(killWaitTimeout):
+		log.Errorf("Command [%v] didn't terminate after being killed", cmd.Path)
+	}
 }
@@ -19,14 +19,15 @@ package fetcher
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ercole-io/ercole-agent-rhel5/logger"
 )
 
 // RunCommandAs utility
-func runCommandAs(log logger.Logger, u *User, commandName string, args ...string) (stdout, stderr []byte, exitCode int, err error) {
+func runCommandAs(log logger.Logger, u *User, env []string, timeout time.Duration, cancel <-chan struct{}, commandName string, args ...string) *FetchResult {
 	msg := "Not yet implemented for Windows"
 	log.Error(msg)
 
-	return nil, nil, -1, fmt.Errorf(msg)
+	return &FetchResult{Command: commandName, ExitCode: -1, Err: fmt.Errorf(msg)}
 }
@@ -0,0 +1,109 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package fetcher
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// environment return the environment of the fetchers run as u, or as the agent user if u is nil.
+// Only the configured allowed variables are inherited from the agent.
+func (lf *LinuxFetcherImpl) environment(u *User) []string {
+	conf := lf.configuration.FetcherEnvironment
+
+	vars := []string{"PATH", "HOME", "USER", "LOGNAME", "LANG", "LC_ALL", "NLS_LANG"}
+	values := map[string]string{
+		"PATH":     conf.Path,
+		"HOME":     os.Getenv("HOME"),
+		"USER":     os.Getenv("USER"),
+		"LOGNAME":  os.Getenv("LOGNAME"),
+		"LANG":     conf.Lang,
+		"LC_ALL":   conf.Lang,
+		"NLS_LANG": conf.NLSLang,
+	}
+
+	if u != nil {
+		values["HOME"] = u.HomeDir
+		values["USER"] = u.Name
+		values["LOGNAME"] = u.Name
+	}
+
+	for _, name := range conf.AllowedVariables {
+		if _, ok := values[name]; ok {
+			continue
+		}
+
+		if value := os.Getenv(name); value != "" {
+			vars = append(vars, name)
+			values[name] = value
+		}
+	}
+
+	env := make([]string, 0, len(vars))
+	for _, name := range vars {
+		if values[name] != "" {
+			env = append(env, name+"="+values[name])
+		}
+	}
+
+	return env
+}
+
+// lookupGroupIDs return the ids of the groups of username, its primary group gid included,
+// as listed in /etc/group
+func lookupGroupIDs(username string, gid uint32) ([]uint32, error) {
+	groups := []uint32{gid}
+
+	file, err := os.Open("/etc/group")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// group_name:password:GID:user_list
+		fields := strings.Split(scanner.Text(), ":")
+		if len(fields) != 4 {
+			continue
+		}
+
+		for _, member := range strings.Split(fields[3], ",") {
+			if strings.TrimSpace(member) != username {
+				continue
+			}
+
+			groupID, err := strconv.ParseUint(fields[2], 10, 32)
+			if err != nil {
+				break
+			}
+
+			if uint32(groupID) != gid {
+				groups = append(groups, uint32(groupID))
+			}
+			break
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
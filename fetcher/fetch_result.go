@@ -0,0 +1,209 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package fetcher
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ercole-io/ercole-agent-rhel5/marshal"
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+// Maximum size of the outputs captured from a fetcher
+const (
+	maxStdoutSize = 64 << 20
+	maxStderrSize = 64 << 10
+)
+
+// FetchResult holds the outcome of a fetcher execution
+type FetchResult struct {
+	Command         string
+	Stdout          []byte
+	Stderr          []byte
+	ExitCode        int
+	Signal          string
+	TimedOut        bool
+	Canceled        bool
+	StdoutTruncated bool
+	StderrTruncated bool
+	Duration        time.Duration
+	Err             error
+}
+
+// oracleErrorRegexp matches the errors as sqlplus prints them, at the beginning of the line,
+// and not the ORA- codes which are data, like the errors of v$archive_dest
+var oracleErrorRegexp = regexp.MustCompile(`^(ORA|SP2)-[0-9]{4,5}\b`)
+
+// OracleErrors return the ORA- and SP2- error lines printed by sqlplus
+func (r *FetchResult) OracleErrors() []string {
+	oracleErrors := make([]string, 0)
+
+	for _, output := range [][]byte{r.Stdout, r.Stderr} {
+		scanner := bufio.NewScanner(bytes.NewReader(output))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if oracleErrorRegexp.MatchString(line) {
+				oracleErrors = append(oracleErrors, line)
+			}
+		}
+	}
+
+	return oracleErrors
+}
+
+// Status return a short description of how the fetcher terminated
+func (r *FetchResult) Status() string {
+	switch {
+	case r.TimedOut:
+		return "timed out"
+	case r.Canceled:
+		return "canceled"
+	case r.Signal != "":
+		return fmt.Sprintf("killed by signal %s", r.Signal)
+	default:
+		return fmt.Sprintf("exit code %d", r.ExitCode)
+	}
+}
+
+// errorClass return the model.CollectionError class of a failed fetcher
+func (r *FetchResult) errorClass() string {
+	switch {
+	case r.TimedOut:
+		return model.CollectionErrorClassTimeout
+	case r.Canceled:
+		return model.CollectionErrorClassCancel
+	case r.Signal != "":
+		return model.CollectionErrorClassSignal
+	case r.ExitCode > 0:
+		return model.CollectionErrorClassExit
+	case r.Err == nil:
+		// sqlplus exits with 0 after printing errors
+		return model.CollectionErrorClassOracle
+	default:
+		return model.CollectionErrorClassExec
+	}
+}
+
+// FetchError is returned when a fetcher fails
+type FetchError struct {
+	Fetcher      string
+	Class        string
+	Status       string
+	Stderr       string
+	OracleErrors []string
+	Duration     time.Duration
+	Err          error
+}
+
+func newFetchError(fetcherName string, result *FetchResult) *FetchError {
+	return &FetchError{
+		Fetcher:      fetcherName,
+		Class:        result.errorClass(),
+		Status:       result.Status(),
+		Stderr:       strings.TrimSpace(string(result.Stderr)),
+		OracleErrors: result.OracleErrors(),
+		Duration:     result.Duration,
+		Err:          result.Err,
+	}
+}
+
+func (e *FetchError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("Fetcher [%s] printed Oracle errors: [%s]", e.Fetcher, strings.Join(e.OracleErrors, "; "))
+	}
+
+	msg := fmt.Sprintf("Fetcher [%s] failed with %s: [%v]", e.Fetcher, e.Status, e.Err)
+
+	if len(e.OracleErrors) > 0 {
+		msg += fmt.Sprintf(" Oracle errors: [%s]", strings.Join(e.OracleErrors, "; "))
+	} else if e.Stderr != "" {
+		msg += fmt.Sprintf(" stderr: [%s]", e.Stderr)
+	}
+
+	return msg
+}
+
+// MarshalError is returned when the output of a fetcher can't be marshalled
+type MarshalError struct {
+	Fetcher string
+	DBName  string
+	Line    string
+	Reason  string
+}
+
+func (e *MarshalError) Error() string {
+	msg := fmt.Sprintf("Can't marshal output of fetcher [%s]", e.Fetcher)
+
+	if e.DBName != "" {
+		msg += fmt.Sprintf(" of database [%s]", e.DBName)
+	}
+
+	if e.Line != "" {
+		msg += fmt.Sprintf(" at line [%s]", e.Line)
+	}
+
+	return msg + ": " + e.Reason
+}
+
+// recoverMarshal must be deferred by the fetchers: it converts a panic raised
+// while marshalling their output into a MarshalError
+func recoverMarshal(fetcherName, dbName string, err *error) {
+	if r := recover(); r != nil {
+		marshalErr := &MarshalError{
+			Fetcher: fetcherName,
+			DBName:  dbName,
+			Reason:  fmt.Sprint(r),
+		}
+
+		if lineErr, ok := r.(*marshal.LineError); ok {
+			marshalErr.Line = lineErr.Line
+			marshalErr.Reason = fmt.Sprint(lineErr.Cause)
+		}
+
+		*err = marshalErr
+	}
+}
+
+// cappedBuffer stores at most limit bytes, silently discarding the rest
+type cappedBuffer struct {
+	bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func newCappedBuffer(limit int) *cappedBuffer {
+	return &cappedBuffer{limit: limit}
+}
+
+// Write never fails, so the command isn't killed by a broken pipe when the limit is reached
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	room := b.limit - b.Len()
+	if len(p) <= room {
+		return b.Buffer.Write(p)
+	}
+
+	b.truncated = true
+	if room > 0 {
+		b.Buffer.Write(p[:room])
+	}
+
+	return len(p), nil
+}
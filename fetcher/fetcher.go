@@ -16,6 +16,9 @@
 package fetcher
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/ercole-io/ercole-agent-rhel5/agentmodel"
 	"github.com/ercole-io/ercole-agent-rhel5/config"
 	"github.com/ercole-io/ercole-agent-rhel5/model"
@@ -27,45 +30,78 @@ type Fetcher interface {
 	SetUserAsCurrent() error
 
 	// Operating system fetchers
-	GetHost() model.Host
+	GetHost() (model.Host, error)
 	GetFilesystems() ([]model.Filesystem, error)
-	GetClustersMembershipStatus() model.ClusterMembershipStatus
+	GetClustersMembershipStatus() (model.ClusterMembershipStatus, error)
 
 	// Virtualization fetcher
-	GetClusters(hv config.Hypervisor) []model.ClusterInfo
-	GetVirtualMachines(hv config.Hypervisor) map[string][]model.VMInfo
+	GetClusters(hv config.Hypervisor) ([]model.ClusterInfo, error)
+	GetVirtualMachines(hv config.Hypervisor) (map[string][]model.VMInfo, error)
 
 	// Oracle/Database fetchers
-	GetOracleDatabaseOratabEntries() []agentmodel.OratabEntry
-	GetOracleDatabaseRunningDatabases() []string
-	GetOracleDatabaseDbStatus(entry agentmodel.OratabEntry) string
-	GetOracleDatabaseMountedDb(entry agentmodel.OratabEntry) model.OracleDatabase
-	GetOracleDatabaseDbVersion(entry agentmodel.OratabEntry) string
-	RunOracleDatabaseStats(entry agentmodel.OratabEntry)
-	GetOracleDatabaseOpenDb(entry agentmodel.OratabEntry) model.OracleDatabase
-	GetOracleDatabaseTablespaces(entry agentmodel.OratabEntry) []model.OracleDatabaseTablespace
-	GetOracleDatabaseSchemas(entry agentmodel.OratabEntry) []model.OracleDatabaseSchema
-	GetOracleDatabasePatches(entry agentmodel.OratabEntry, dbVersion string) []model.OracleDatabasePatch
-	GetOracleDatabaseFeatureUsageStat(entry agentmodel.OratabEntry, dbVersion string) []model.OracleDatabaseFeatureUsageStat
-	GetOracleDatabaseLicenses(entry agentmodel.OratabEntry, dbVersion, hardwareAbstractionTechnology string) []model.OracleDatabaseLicense
-	GetOracleDatabaseADDMs(entry agentmodel.OratabEntry) []model.OracleDatabaseAddm
-	GetOracleDatabaseSegmentAdvisors(entry agentmodel.OratabEntry) []model.OracleDatabaseSegmentAdvisor
-	GetOracleDatabasePSUs(entry agentmodel.OratabEntry, dbVersion string) []model.OracleDatabasePSU
-	GetOracleDatabaseBackups(entry agentmodel.OratabEntry) []model.OracleDatabaseBackup
-	GetOracleDatabaseCheckPDB(entry agentmodel.OratabEntry) bool
-	GetOracleDatabasePDBs(entry agentmodel.OratabEntry) []model.OracleDatabasePluggableDatabase
-	GetOracleDatabasePDBTablespaces(entry agentmodel.OratabEntry, pdb string) []model.OracleDatabaseTablespace
-	GetOracleDatabasePDBSchemas(entry agentmodel.OratabEntry, pdb string) []model.OracleDatabaseSchema
-	GetOracleDatabaseGrantsDba(entry agentmodel.OratabEntry) []model.OracleGrantDba
-	GetOracleDatabasePartitionings(entry agentmodel.OratabEntry) []model.OracleDatabasePartitioning
+	GetOracleDatabaseOratabEntries() ([]agentmodel.OratabEntry, error)
+	GetOracleDatabaseRunningDatabases() ([]string, error)
+	GetOracleDatabaseRunningHomes() ([]agentmodel.OratabEntry, error)
+	GetOracleDatabaseClusterwareDatabases() ([]agentmodel.OratabEntry, error)
+	GetOracleDatabaseDbStatus(entry agentmodel.OratabEntry) (string, error)
+	GetOracleDatabaseMountedDb(entry agentmodel.OratabEntry) (model.OracleDatabase, error)
+	GetOracleDatabaseDbVersion(entry agentmodel.OratabEntry) (string, error)
+	RunOracleDatabaseStats(entry agentmodel.OratabEntry) error
+	GetOracleDatabaseOpenDb(entry agentmodel.OratabEntry) (model.OracleDatabase, error)
+	GetOracleDatabaseTablespaces(entry agentmodel.OratabEntry) ([]model.OracleDatabaseTablespace, error)
+	GetOracleDatabaseSchemas(entry agentmodel.OratabEntry) ([]model.OracleDatabaseSchema, error)
+	GetOracleDatabasePatches(entry agentmodel.OratabEntry, dbVersion string) ([]model.OracleDatabasePatch, error)
+	GetOracleDatabaseFeatureUsageStat(entry agentmodel.OratabEntry, dbVersion string) ([]model.OracleDatabaseFeatureUsageStat, error)
+	GetOracleDatabaseLicenses(entry agentmodel.OratabEntry, dbVersion, hardwareAbstractionTechnology string) ([]model.OracleDatabaseLicense, error)
+	GetOracleDatabaseADDMs(entry agentmodel.OratabEntry) ([]model.OracleDatabaseAddm, error)
+	GetOracleDatabaseSegmentAdvisors(entry agentmodel.OratabEntry) ([]model.OracleDatabaseSegmentAdvisor, error)
+	GetOracleDatabasePSUs(entry agentmodel.OratabEntry, dbVersion string) ([]model.OracleDatabasePSU, error)
+	GetOracleDatabaseBackups(entry agentmodel.OratabEntry) ([]model.OracleDatabaseBackup, error)
+	GetOracleDatabaseBackupHistory(entry agentmodel.OratabEntry) ([]model.OracleDatabaseBackupJob, model.OracleDatabaseLastBackups, error)
+	GetOracleDatabaseInstances(entry agentmodel.OratabEntry) (bool, []model.OracleDatabaseInstance, error)
+	GetOracleDatabaseDataguard(entry agentmodel.OratabEntry) (*model.OracleDatabaseDataguardStatus, error)
+	GetOracleDatabaseCheckPDB(entry agentmodel.OratabEntry) (bool, error)
+	GetOracleDatabasePDBs(entry agentmodel.OratabEntry) ([]model.OracleDatabasePluggableDatabase, error)
+	GetOracleDatabasePDBTablespaces(entry agentmodel.OratabEntry, pdb string) ([]model.OracleDatabaseTablespace, error)
+	GetOracleDatabasePDBSchemas(entry agentmodel.OratabEntry, pdb string) ([]model.OracleDatabaseSchema, error)
+	GetOracleDatabasePDBServices(entry agentmodel.OratabEntry, pdb string) ([]model.OracleDatabaseService, error)
+	GetOracleDatabasePDBParameters(entry agentmodel.OratabEntry, pdb string) ([]model.OracleDatabaseParameter, error)
+	GetOracleDatabaseParameters(entry agentmodel.OratabEntry) ([]model.OracleDatabaseParameter, error)
+	GetOracleDatabaseServices(entry agentmodel.OratabEntry) ([]model.OracleDatabaseService, error)
+	GetOracleDatabaseClusterwareServices(entry agentmodel.OratabEntry, dbUniqueName string) ([]agentmodel.ClusterwareService, error)
+	GetOracleDatabaseGrantsDba(entry agentmodel.OratabEntry) ([]model.OracleGrantDba, error)
+	GetOracleDatabaseSecurity(entry agentmodel.OratabEntry, dbVersion string) (*model.OracleDatabaseSecurity, error)
+	GetOracleDatabasePartitionings(entry agentmodel.OratabEntry) ([]model.OracleDatabasePartitioning, error)
 
 	// Oracle/Exadata fetchers
-	GetOracleExadataComponents() []model.OracleExadataComponent
-	GetOracleExadataCellDisks() map[agentmodel.StorageServerName][]model.OracleExadataCellDisk
+	GetOracleExadataComponents() ([]model.OracleExadataComponent, error)
+	GetOracleExadataCellDisks() (map[agentmodel.StorageServerName][]model.OracleExadataCellDisk, error)
+	GetOracleASM() (*model.OracleASMFeature, error)
 }
 
 // User struct
 type User struct {
 	Name     string
 	UID, GID uint32
+	HomeDir  string
+	Groups   []uint32
+}
+
+// TimeoutError is returned when a fetcher doesn't terminate within its timeout
+type TimeoutError struct {
+	Command string
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("Command [%s] timed out after %v", e.Command, e.Timeout)
+}
+
+// CanceledError is returned when a fetcher is killed, or not run at all, because the agent is shutting down
+type CanceledError struct {
+	Command string
+}
+
+func (e *CanceledError) Error() string {
+	return fmt.Sprintf("Command [%s] canceled", e.Command)
 }
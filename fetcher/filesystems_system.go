@@ -0,0 +1,134 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package fetcher
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/ercole-io/ercole-agent-rhel5/marshal"
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+// statfsTimeout is how long to wait for the filesystems to answer statfs,
+// the ones that don't, e.g. hung NFS mounts, are skipped
+const statfsTimeout = 30 * time.Second
+
+// pendingStatfs holds the mountpoints whose statfs hasn't returned yet, also of previous collections.
+// They are skipped until it returns, so that a hung mount doesn't block a new goroutine at each collection
+var pendingStatfs = struct {
+	sync.Mutex
+	mountpoints map[string]bool
+}{mountpoints: make(map[string]bool)}
+
+// StatfsError is returned with the filesystems which were collected when some didn't answer statfs
+type StatfsError struct {
+	MountPoints []string
+}
+
+func (e *StatfsError) Error() string {
+	return fmt.Sprintf("Filesystems %v didn't answer statfs, skipped", e.MountPoints)
+}
+
+type statfsResult struct {
+	index int
+	fs    model.Filesystem
+	err   error
+}
+
+// getFilesystemsFromSystem get the filesystems reading /proc/mounts and calling statfs on each of them,
+// without running the filesystem fetcher. The filesystems which don't answer are returned in a *StatfsError
+func (lf *LinuxFetcherImpl) getFilesystemsFromSystem() (filesystems []model.Filesystem, err error) {
+	procMounts, err := ioutil.ReadFile("/proc/mounts")
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("filesystem", "", &err)
+
+	mounts := marshal.Mounts(procMounts)
+
+	// statfs can't be interrupted: the goroutines of hung mounts stay blocked, the results channel is
+	// buffered so that they can terminate if the mount recovers
+	results := make(chan statfsResult, len(mounts))
+	answered := make([]bool, len(mounts))
+	started := 0
+
+	pendingStatfs.Lock()
+	for i := range mounts {
+		if pendingStatfs.mountpoints[mounts[i].MountedOn] {
+			continue
+		}
+		pendingStatfs.mountpoints[mounts[i].MountedOn] = true
+		started++
+
+		go func(index int, fs model.Filesystem) {
+			fs, err := statfs(fs)
+
+			pendingStatfs.Lock()
+			delete(pendingStatfs.mountpoints, fs.MountedOn)
+			pendingStatfs.Unlock()
+
+			results <- statfsResult{index, fs, err}
+		}(i, mounts[i])
+	}
+	pendingStatfs.Unlock()
+
+	collected := make([]*model.Filesystem, len(mounts))
+	deadline := time.After(statfsTimeout)
+
+wait:
+	for received := 0; received < started; received++ {
+		select {
+		case result := <-results:
+			answered[result.index] = true
+
+			if result.err != nil {
+				lf.log.Warnf("Can't statfs filesystem [%s], skipped: %v", result.fs.MountedOn, result.err)
+				continue
+			}
+
+			// Like df, filesystems without blocks aren't listed
+			if result.fs.Size > 0 {
+				collected[result.index] = &result.fs
+			}
+		case <-deadline:
+			break wait
+		}
+	}
+
+	filesystems = make([]model.Filesystem, 0, len(mounts))
+	for _, fs := range collected {
+		if fs != nil {
+			filesystems = append(filesystems, *fs)
+		}
+	}
+
+	var skipped []string
+	for i, fs := range mounts {
+		if !answered[i] {
+			skipped = append(skipped, fs.MountedOn)
+		}
+	}
+
+	if len(skipped) > 0 {
+		return filesystems, &StatfsError{MountPoints: skipped}
+	}
+
+	return filesystems, nil
+}
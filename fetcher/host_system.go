@@ -0,0 +1,112 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package fetcher
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ercole-io/ercole-agent-rhel5/marshal"
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+// dmiFiles are the files of /sys/class/dmi/id used to detect the hypervisor
+var dmiFiles = []string{"sys_vendor", "product_name", "bios_vendor", "bios_version"}
+
+// getHostFromSystem get the host info reading /proc, /sys and /etc, without running the host fetcher
+func (lf *LinuxFetcherImpl) getHostFromSystem() (host model.Host, err error) {
+	files := marshal.HostSystemFiles{DMI: make(map[string]string)}
+
+	if files.Hostname, err = os.Hostname(); err != nil {
+		return model.Host{}, err
+	}
+
+	if files.CPUInfo, err = ioutil.ReadFile("/proc/cpuinfo"); err != nil {
+		return model.Host{}, err
+	}
+
+	if files.MemInfo, err = ioutil.ReadFile("/proc/meminfo"); err != nil {
+		return model.Host{}, err
+	}
+
+	if files.KernelName, err = readSystemFile("/proc/sys/kernel/ostype"); err != nil {
+		return model.Host{}, err
+	}
+
+	if files.KernelVersion, err = readSystemFile("/proc/sys/kernel/osrelease"); err != nil {
+		return model.Host{}, err
+	}
+
+	// The following files are optional: RHEL5 has neither os-release nor DMI in /sys
+	files.OSRelease, _ = ioutil.ReadFile("/etc/os-release")
+	files.RedhatRelease, _ = ioutil.ReadFile("/etc/redhat-release")
+
+	if len(files.OSRelease) == 0 && len(files.RedhatRelease) == 0 {
+		releases, _ := filepath.Glob("/etc/*-release")
+		for _, release := range releases {
+			if content, err := ioutil.ReadFile(release); err == nil && len(content) > 0 {
+				files.OtherRelease = content
+				break
+			}
+		}
+	}
+
+	for _, name := range dmiFiles {
+		files.DMI[name], _ = readSystemFile(filepath.Join("/sys/class/dmi/id", name))
+	}
+	files.HypervisorType, _ = readSystemFile("/sys/hypervisor/type")
+
+	if files.DMI["sys_vendor"] == "" && files.DMI["product_name"] == "" {
+		files.KernelLog = lf.readKernelLog()
+	}
+
+	defer recoverMarshal("host", "", &err)
+
+	return marshal.HostFromSystem(files), nil
+}
+
+// readKernelLog returns the output of dmesg and the content of /var/log/dmesg*, where the host fetcher
+// looks for the hypervisor. Errors are ignored: the ring buffer may be restricted or already rotated.
+// dmesg is run like the fetchers, with their user, environment, timeout and cancellation
+func (lf *LinuxFetcherImpl) readKernelLog() []byte {
+	var kernelLog []byte
+
+	result := runCommandAs(lf.log, lf.fetcherUser, lf.environment(lf.fetcherUser), lf.getTimeout("dmesg"), lf.cancel, "/bin/dmesg")
+	lf.logResult("dmesg", result)
+	if result.Err == nil {
+		kernelLog = result.Stdout
+	}
+
+	logs, _ := filepath.Glob("/var/log/dmesg*")
+	for _, log := range logs {
+		if content, err := ioutil.ReadFile(log); err == nil {
+			kernelLog = append(kernelLog, content...)
+		}
+	}
+
+	return kernelLog
+}
+
+func readSystemFile(path string) (string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(content)), nil
+}
@@ -0,0 +1,296 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package fetcher
+
+import (
+	"bufio"
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/ercole-io/ercole-agent-rhel5/logger"
+)
+
+// ManifestPublicKey is the base64 DER (PKIX) RSA public key which signs the manifest,
+// it's set at build time. If empty no fetcher is run, unless AllowUnsignedManifest is set in the configuration.
+var ManifestPublicKey = ""
+
+// Names of the manifest of the fetchers and SQL scripts and of its signature, in the agent directory
+const (
+	manifestFileName          = "MANIFEST"
+	manifestSignatureFileName = "MANIFEST.sig"
+)
+
+// IntegrityError is returned when a fetcher isn't run because
+// the fetchers or the SQL scripts could have been tampered with
+type IntegrityError struct {
+	Fetcher string
+	Path    string
+	Reason  string
+}
+
+func (e *IntegrityError) Error() string {
+	return fmt.Sprintf("Integrity check of [%s] failed, fetcher [%s] not run: %s", e.Path, e.Fetcher, e.Reason)
+}
+
+// manifest holds the SHA-256 hashes of the files shipped in the fetch/ and sql/ directories,
+// by path relative to the agent directory
+type manifest struct {
+	baseDir string
+	hashes  map[string]string
+}
+
+// sqlScriptReference matches the SQL scripts run by a fetcher, e.g. ${ERCOLE_HOME}/sql/ts.sql
+var sqlScriptReference = regexp.MustCompile(`sql/[A-Za-z0-9_.-]+\.sql`)
+
+// integrityChecker verifies the fetchers before their execution
+type integrityChecker struct {
+	log           logger.Logger
+	baseDir       string
+	allowUnsigned bool
+
+	warnUnsignedOnce sync.Once
+
+	// manifest is the last verified manifest, it's verified again only when
+	// the manifest or its signature are replaced or modified
+	lock          sync.Mutex
+	manifest      *manifest
+	manifestInfo  os.FileInfo
+	signatureInfo os.FileInfo
+}
+
+func newIntegrityChecker(log logger.Logger, baseDir string, allowUnsigned bool) *integrityChecker {
+	return &integrityChecker{log: log, baseDir: baseDir, allowUnsigned: allowUnsigned}
+}
+
+// check verifies that command and the SQL scripts it runs are listed in the signed manifest,
+// match their hashes and aren't writable by others
+func (c *integrityChecker) check(fetcherName, command string) error {
+	m, err := c.getManifest()
+	if err != nil {
+		return &IntegrityError{Fetcher: fetcherName, Path: filepath.Join(c.baseDir, manifestFileName), Reason: err.Error()}
+	}
+
+	content, err := m.checkFile(command)
+	if err != nil {
+		return &IntegrityError{Fetcher: fetcherName, Path: command, Reason: err.Error()}
+	}
+
+	for _, reference := range sqlScriptReference.FindAllString(string(content), -1) {
+		path := filepath.Join(c.baseDir, reference)
+
+		if _, err := m.checkFile(path); err != nil {
+			return &IntegrityError{Fetcher: fetcherName, Path: path, Reason: err.Error()}
+		}
+	}
+
+	return nil
+}
+
+// getManifest returns the verified manifest, loading it again if it or its signature changed
+func (c *integrityChecker) getManifest() (*manifest, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	manifestInfo, err := os.Stat(filepath.Join(c.baseDir, manifestFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	// A missing signature is reported by loadManifest, when it's needed
+	signatureInfo, _ := os.Stat(filepath.Join(c.baseDir, manifestSignatureFileName))
+
+	if c.manifest != nil && sameFileInfo(c.manifestInfo, manifestInfo) && sameFileInfo(c.signatureInfo, signatureInfo) {
+		return c.manifest, nil
+	}
+
+	m, err := c.loadManifest()
+	if err != nil {
+		c.manifest = nil
+		return nil, err
+	}
+
+	c.manifest, c.manifestInfo, c.signatureInfo = m, manifestInfo, signatureInfo
+
+	return m, nil
+}
+
+// sameFileInfo returns true if a and b are the same file, not modified in between
+func sameFileInfo(a, b os.FileInfo) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	return os.SameFile(a, b) && a.ModTime().Equal(b.ModTime()) && a.Size() == b.Size()
+}
+
+func (c *integrityChecker) loadManifest() (*manifest, error) {
+	content, err := ioutil.ReadFile(filepath.Join(c.baseDir, manifestFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	if ManifestPublicKey == "" {
+		if !c.allowUnsigned {
+			return nil, fmt.Errorf("Agent built without the manifest public key, " +
+				"set AllowUnsignedManifest in the configuration to run fetchers with an unsigned manifest")
+		}
+
+		c.warnUnsignedOnce.Do(func() {
+			c.log.Warnf("Agent built without the manifest public key and AllowUnsignedManifest set, " +
+				"the signature of the manifest isn't verified")
+		})
+	} else {
+		signature, err := ioutil.ReadFile(filepath.Join(c.baseDir, manifestSignatureFileName))
+		if err != nil {
+			return nil, err
+		}
+
+		if err := verifySignature(content, signature); err != nil {
+			return nil, err
+		}
+	}
+
+	return parseManifest(c.baseDir, content)
+}
+
+func verifySignature(content, signature []byte) error {
+	der, err := base64.StdEncoding.DecodeString(ManifestPublicKey)
+	if err != nil {
+		return fmt.Errorf("Invalid manifest public key: %v", err)
+	}
+
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return fmt.Errorf("Invalid manifest public key: %v", err)
+	}
+
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("Invalid manifest public key: not an RSA key")
+	}
+
+	hash := sha256.Sum256(content)
+	if err := rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, hash[:], signature); err != nil {
+		return fmt.Errorf("Invalid manifest signature: %v", err)
+	}
+
+	return nil
+}
+
+// parseManifest parse a manifest in the sha256sum format, "<hash>  <relative path>" on each line
+func parseManifest(baseDir string, content []byte) (*manifest, error) {
+	m := &manifest{baseDir: baseDir, hashes: make(map[string]string)}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 || len(fields[0]) != sha256.Size*2 {
+			return nil, fmt.Errorf("Invalid manifest line: [%s]", line)
+		}
+
+		relPath := filepath.Clean(strings.TrimPrefix(fields[1], "*"))
+		if filepath.IsAbs(relPath) || strings.HasPrefix(relPath, "..") {
+			return nil, fmt.Errorf("Invalid manifest path: [%s]", relPath)
+		}
+
+		m.hashes[relPath] = strings.ToLower(fields[0])
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// checkPermissions refuse path if it, or one of the directories up to the agent directory,
+// is group or world writable
+func (m *manifest) checkPermissions(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("Not a regular file")
+	}
+
+	if info.Mode().Perm()&0022 != 0 {
+		return fmt.Errorf("File is group or world writable (%v)", info.Mode().Perm())
+	}
+
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return err
+		}
+
+		if info.Mode().Perm()&0022 != 0 {
+			return fmt.Errorf("Directory [%s] is group or world writable (%v)", dir, info.Mode().Perm())
+		}
+
+		if dir == m.baseDir || dir == filepath.Dir(dir) {
+			return nil
+		}
+	}
+}
+
+// checkFile verifies that path is listed in the manifest, with its permissions and hash,
+// and returns its content
+func (m *manifest) checkFile(path string) ([]byte, error) {
+	relPath, err := filepath.Rel(m.baseDir, path)
+	if err != nil {
+		return nil, err
+	}
+
+	expected, ok := m.hashes[relPath]
+	if !ok {
+		return nil, fmt.Errorf("Not listed in the manifest")
+	}
+
+	if err := m.checkPermissions(path); err != nil {
+		return nil, err
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(content)
+	if actual := hex.EncodeToString(hash[:]); actual != expected {
+		return nil, fmt.Errorf("SHA-256 [%s] doesn't match the manifest [%s]", actual, expected)
+	}
+
+	return content, nil
+}
@@ -16,9 +16,12 @@
 package fetcher
 
 import (
+	"fmt"
+	"os"
 	"os/user"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ercole-io/ercole-agent-rhel5/agentmodel"
 	"github.com/ercole-io/ercole-agent-rhel5/config"
@@ -33,16 +36,26 @@ type LinuxFetcherImpl struct {
 	configuration config.Configuration
 	log           logger.Logger
 	fetcherUser   *User
+	cancel        <-chan struct{}
+	integrity     *integrityChecker
 }
 
 const notImplementedLinux = "Not yet implemented for GNU/Linux"
 
-// NewLinuxFetcherImpl constructor
-func NewLinuxFetcherImpl(conf config.Configuration, log logger.Logger) *LinuxFetcherImpl {
+// fetchersPrintingOracleErrors are the fetchers whose output is expected to contain
+// Oracle errors, interpreted by their caller, e.g. ORA-01034 for a database which isn't running
+var fetchersPrintingOracleErrors = map[string]bool{
+	"dbstatus": true,
+}
+
+// NewLinuxFetcherImpl constructor, running fetchers are killed when cancel is closed
+func NewLinuxFetcherImpl(conf config.Configuration, log logger.Logger, cancel <-chan struct{}) *LinuxFetcherImpl {
 	return &LinuxFetcherImpl{
 		conf,
 		log,
 		nil,
+		cancel,
+		newIntegrityChecker(log, config.GetBaseDir(), conf.AllowUnsignedManifest),
 	}
 }
 
@@ -76,7 +89,13 @@ func (lf *LinuxFetcherImpl) getUserInfo(username string) (*User, error) {
 		return nil, err
 	}
 
-	return &User{u.Name, uint32(intUID), uint32(intGID)}, nil
+	groups, err := lookupGroupIDs(u.Username, uint32(intGID))
+	if err != nil {
+		lf.log.Errorf("Can't lookup groups of username [%s], error: [%v]", username, err)
+		return nil, err
+	}
+
+	return &User{u.Username, uint32(intUID), uint32(intGID), u.HomeDir, groups}, nil
 }
 
 // SetUserAsCurrent set user used by fetcher to run commands as current process user
@@ -86,86 +105,177 @@ func (lf *LinuxFetcherImpl) SetUserAsCurrent() error {
 }
 
 // Execute execute bash script by name
-func (lf *LinuxFetcherImpl) execute(fetcherName string, args ...string) []byte {
+func (lf *LinuxFetcherImpl) execute(fetcherName string, args ...string) ([]byte, error) {
 	commandName := config.GetBaseDir() + "/fetch/linux/" + fetcherName + ".sh"
 	lf.log.Infof("Fetching %s %s", commandName, strings.Join(args, " "))
 
-	stdout, stderr, exitCode, err := runCommandAs(lf.log, lf.fetcherUser, commandName, args...)
+	if err := lf.integrity.check(fetcherName, commandName); err != nil {
+		return nil, err
+	}
+
+	result := runCommandAs(lf.log, lf.fetcherUser, lf.environment(lf.fetcherUser), lf.getTimeout(fetcherName), lf.cancel, commandName, args...)
+	lf.logResult(fetcherName, result)
+
+	if result.Err != nil {
+		return nil, newFetchError(fetcherName, result)
+	}
+
+	// The output is incomplete, the section is reported as not collected
+	if !fetchersPrintingOracleErrors[fetcherName] && len(result.OracleErrors()) > 0 {
+		return nil, newFetchError(fetcherName, result)
+	}
+
+	return result.Stdout, nil
+}
 
-	lf.log.Debugf("Fetcher [%s] stdout: [%v]", fetcherName, strings.TrimSpace(string(stdout)))
+// logResult log outputs, exit status and Oracle errors of a fetcher
+func (lf *LinuxFetcherImpl) logResult(fetcherName string, result *FetchResult) {
+	lf.log.Debugf("Fetcher [%s] %s in %v stdout: [%v]", fetcherName, result.Status(), result.Duration, strings.TrimSpace(string(result.Stdout)))
 
-	if len(stderr) > 0 {
-		format := "Fetcher [%s] exitCode: [%v] stderr: [%v]"
-		args := []interface{}{fetcherName, exitCode, strings.TrimSpace(string(stderr))}
+	if len(result.Stderr) > 0 {
+		format := "Fetcher [%s] %s stderr: [%v]"
+		args := []interface{}{fetcherName, result.Status(), strings.TrimSpace(string(result.Stderr))}
 
-		if exitCode == 0 {
+		if result.ExitCode == 0 {
 			lf.log.Debugf(format, args...)
 		} else {
 			lf.log.Errorf(format, args...)
 		}
 	}
 
-	if err != nil {
-		if fetcherName == "dbstatus" {
-			return []byte("UNREACHABLE")
-		}
+	if result.StderrTruncated {
+		lf.log.Warnf("Fetcher [%s] stderr truncated to %d bytes", fetcherName, maxStderrSize)
+	}
+
+	if oracleErrors := result.OracleErrors(); len(oracleErrors) > 0 {
+		lf.log.Warnf("Fetcher [%s] printed Oracle errors: [%v]", fetcherName, strings.Join(oracleErrors, "; "))
+	}
+}
 
-		lf.log.Fatalf("Fatal error running [%s %s]: [%v]", commandName, strings.Join(args, " "), err)
+// getTimeout return the timeout of the fetcher, overridden by FetcherTimeouts if configured
+func (lf *LinuxFetcherImpl) getTimeout(fetcherName string) time.Duration {
+	if timeout, ok := lf.configuration.FetcherTimeouts[fetcherName]; ok {
+		return time.Duration(timeout) * time.Second
 	}
 
-	return stdout
+	return time.Duration(lf.configuration.FetcherTimeout) * time.Second
 }
 
 // executePwsh execute pwsh script by name
-func (lf *LinuxFetcherImpl) executePwsh(fetcherName string, args ...string) []byte {
+func (lf *LinuxFetcherImpl) executePwsh(fetcherName string, args ...string) ([]byte, error) {
 	scriptPath := config.GetBaseDir() + "/fetch/linux/" + fetcherName
 	args = append([]string{scriptPath}, args...)
 
 	lf.log.Infof("Fetching %v", scriptPath, strings.Join(args, " "))
 
-	stdout, stderr, exitCode, err := runCommandAs(lf.log, lf.fetcherUser, "/usr/bin/pwsh", args...)
+	if err := lf.integrity.check(fetcherName, scriptPath); err != nil {
+		return nil, err
+	}
+
+	result := runCommandAs(lf.log, lf.fetcherUser, lf.environment(lf.fetcherUser), lf.getTimeout(fetcherName), lf.cancel, "/usr/bin/pwsh", args...)
+	lf.logResult(fetcherName, result)
 
-	if len(stdout) > 0 {
-		lf.log.Debugf("Fetcher [%s] stdout: [%v]", fetcherName, strings.TrimSpace(string(stdout)))
+	if result.Err != nil {
+		return nil, newFetchError(fetcherName, result)
 	}
 
-	if len(stderr) > 0 {
-		lf.log.Errorf("Fetcher [%s] exitCode: [%v] stderr: [%v]", fetcherName, exitCode, strings.TrimSpace(string(stderr)))
+	return result.Stdout, nil
+}
+
+// GetHost get, reading the system files directly. It falls back to the host fetcher if they can't be read
+func (lf *LinuxFetcherImpl) GetHost() (model.Host, error) {
+	host, err := lf.getHostFromSystem()
+	if err == nil {
+		return host, nil
 	}
 
+	lf.log.Warnf("Can't read host info from the system, falling back to the host fetcher: %v", err)
+
+	return lf.getHostFromFetcher()
+}
+
+func (lf *LinuxFetcherImpl) getHostFromFetcher() (host model.Host, err error) {
+	out, err := lf.execute("host")
 	if err != nil {
-		lf.log.Fatalf("Fatal error running [%s %s]: [%v]", scriptPath, strings.Join(args, " "), err)
+		return model.Host{}, err
 	}
 
-	return stdout
-}
+	defer recoverMarshal("host", "", &err)
 
-// GetHost get
-func (lf *LinuxFetcherImpl) GetHost() model.Host {
-	out := lf.execute("host")
-	return marshal.Host(out)
+	return marshal.Host(out), nil
 }
 
-// GetFilesystems get
+// GetFilesystems get, reading /proc/mounts. It falls back to the filesystem fetcher if it can't be read.
+// The filesystems which don't answer statfs are skipped and returned in a *StatfsError
 func (lf *LinuxFetcherImpl) GetFilesystems() ([]model.Filesystem, error) {
-	out := lf.execute("filesystem")
+	filesystems, err := lf.getFilesystemsFromSystem()
+	if err == nil {
+		return filesystems, nil
+	}
+
+	// The filesystem fetcher would hang on the same mounts
+	if _, ok := err.(*StatfsError); ok {
+		return filesystems, err
+	}
+
+	lf.log.Warnf("Can't read filesystems from the system, falling back to the filesystem fetcher: %v", err)
+
+	return lf.getFilesystemsFromFetcher()
+}
+
+func (lf *LinuxFetcherImpl) getFilesystemsFromFetcher() (filesystems []model.Filesystem, err error) {
+	out, err := lf.execute("filesystem")
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("filesystem", "", &err)
+
 	return marshal.Filesystems(out)
 }
 
-func (lf *LinuxFetcherImpl) GetOracleDatabaseGrantsDba(entry agentmodel.OratabEntry) []model.OracleGrantDba {
-	out := lf.execute("grant_dba", entry.DBName, entry.OracleHome)
-	return marshal_oracle.GrantDba(out)
+func (lf *LinuxFetcherImpl) GetOracleDatabaseGrantsDba(entry agentmodel.OratabEntry) (grants []model.OracleGrantDba, err error) {
+	out, err := lf.execute("grant_dba", entry.DBName, entry.OracleHome)
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("grant_dba", entry.DBName, &err)
+
+	return marshal_oracle.GrantDba(out), nil
+}
+
+// GetOracleDatabaseSecurity get the users, the password profiles and the users with default passwords
+func (lf *LinuxFetcherImpl) GetOracleDatabaseSecurity(entry agentmodel.OratabEntry, dbVersion string) (security *model.OracleDatabaseSecurity, err error) {
+	out, err := lf.execute("security", entry.DBName, dbVersion, entry.OracleHome)
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("security", entry.DBName, &err)
+
+	s := marshal_oracle.Security(out)
+	return &s, nil
 }
 
 // GetOracleDatabaseOratabEntries get
-func (lf *LinuxFetcherImpl) GetOracleDatabaseOratabEntries() []agentmodel.OratabEntry {
-	out := lf.execute("oratab", lf.configuration.Features.OracleDatabase.Oratab)
-	return marshal_oracle.Oratab(out)
+func (lf *LinuxFetcherImpl) GetOracleDatabaseOratabEntries() (entries []agentmodel.OratabEntry, err error) {
+	out, err := lf.execute("oratab", lf.configuration.Features.OracleDatabase.Oratab)
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("oratab", "", &err)
+
+	return marshal_oracle.Oratab(out), nil
 }
 
 // GetOracleDatabaseRunningDatabases get
-func (lf *LinuxFetcherImpl) GetOracleDatabaseRunningDatabases() []string {
-	out := lf.execute("oracle_running_databases")
+func (lf *LinuxFetcherImpl) GetOracleDatabaseRunningDatabases() ([]string, error) {
+	out, err := lf.execute("oracle_running_databases")
+	if err != nil {
+		return nil, err
+	}
 
 	dbs := strings.Split(string(out), "\n")
 
@@ -177,186 +287,461 @@ func (lf *LinuxFetcherImpl) GetOracleDatabaseRunningDatabases() []string {
 		}
 	}
 
-	return ret
+	return ret, nil
+}
+
+// GetOracleDatabaseRunningHomes get the running instances with the ORACLE_HOME of their pmon process
+func (lf *LinuxFetcherImpl) GetOracleDatabaseRunningHomes() (entries []agentmodel.OratabEntry, err error) {
+	out, err := lf.execute("oracle_running_homes")
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("oracle_running_homes", "", &err)
+
+	return marshal_oracle.RunningHomes(out), nil
+}
+
+// GetOracleDatabaseClusterwareDatabases get the instances on this host of the databases registered in Grid Infrastructure
+func (lf *LinuxFetcherImpl) GetOracleDatabaseClusterwareDatabases() (entries []agentmodel.OratabEntry, err error) {
+	out, err := lf.execute("oracle_clusterware_databases")
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("oracle_clusterware_databases", "", &err)
+
+	return marshal_oracle.ClusterwareDatabases(out, hostname), nil
 }
 
 // GetOracleDatabaseDbStatus get
-func (lf *LinuxFetcherImpl) GetOracleDatabaseDbStatus(entry agentmodel.OratabEntry) string {
-	out := lf.execute("dbstatus", entry.DBName, entry.OracleHome)
-	return strings.TrimSpace(string(out))
+func (lf *LinuxFetcherImpl) GetOracleDatabaseDbStatus(entry agentmodel.OratabEntry) (string, error) {
+	out, err := lf.execute("dbstatus", entry.DBName, entry.OracleHome)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(out)), nil
 }
 
 // GetOracleDatabaseMountedDb get
-func (lf *LinuxFetcherImpl) GetOracleDatabaseMountedDb(entry agentmodel.OratabEntry) model.OracleDatabase {
-	out := lf.execute("dbmounted", entry.DBName, entry.OracleHome)
-	return marshal_oracle.Database(out)
+func (lf *LinuxFetcherImpl) GetOracleDatabaseMountedDb(entry agentmodel.OratabEntry) (database model.OracleDatabase, err error) {
+	out, err := lf.execute("dbmounted", entry.DBName, entry.OracleHome)
+	if err != nil {
+		return model.OracleDatabase{}, err
+	}
+
+	defer recoverMarshal("dbmounted", entry.DBName, &err)
+
+	return marshal_oracle.Database(out), nil
 }
 
 // GetOracleDatabaseDbVersion get
-func (lf *LinuxFetcherImpl) GetOracleDatabaseDbVersion(entry agentmodel.OratabEntry) string {
-	out := lf.execute("dbversion", entry.DBName, entry.OracleHome)
-	return strings.Split(string(out), ".")[0]
+func (lf *LinuxFetcherImpl) GetOracleDatabaseDbVersion(entry agentmodel.OratabEntry) (string, error) {
+	out, err := lf.execute("dbversion", entry.DBName, entry.OracleHome)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Split(string(out), ".")[0], nil
 }
 
 // RunOracleDatabaseStats Execute stats script
-func (lf *LinuxFetcherImpl) RunOracleDatabaseStats(entry agentmodel.OratabEntry) {
-	lf.execute("stats", entry.DBName, entry.OracleHome)
+func (lf *LinuxFetcherImpl) RunOracleDatabaseStats(entry agentmodel.OratabEntry) error {
+	_, err := lf.execute("stats", entry.DBName, entry.OracleHome)
+	return err
 }
 
 // GetOracleDatabaseOpenDb get
-func (lf *LinuxFetcherImpl) GetOracleDatabaseOpenDb(entry agentmodel.OratabEntry) model.OracleDatabase {
-	out := lf.execute("db", entry.DBName, entry.OracleHome, strconv.Itoa(lf.configuration.Features.OracleDatabase.AWR))
-	return marshal_oracle.Database(out)
+func (lf *LinuxFetcherImpl) GetOracleDatabaseOpenDb(entry agentmodel.OratabEntry) (database model.OracleDatabase, err error) {
+	out, err := lf.execute("db", entry.DBName, entry.OracleHome, strconv.Itoa(lf.configuration.Features.OracleDatabase.AWR))
+	if err != nil {
+		return model.OracleDatabase{}, err
+	}
+
+	defer recoverMarshal("db", entry.DBName, &err)
+
+	return marshal_oracle.Database(out), nil
 }
 
 // GetOracleDatabaseTablespaces get
-func (lf *LinuxFetcherImpl) GetOracleDatabaseTablespaces(entry agentmodel.OratabEntry) []model.OracleDatabaseTablespace {
-	out := lf.execute("tablespace", entry.DBName, entry.OracleHome)
-	return marshal_oracle.Tablespaces(out)
+func (lf *LinuxFetcherImpl) GetOracleDatabaseTablespaces(entry agentmodel.OratabEntry) (tablespaces []model.OracleDatabaseTablespace, err error) {
+	out, err := lf.execute("tablespace", entry.DBName, entry.OracleHome)
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("tablespace", entry.DBName, &err)
+
+	return marshal_oracle.Tablespaces(out), nil
 }
 
 // GetOracleDatabaseSchemas get
-func (lf *LinuxFetcherImpl) GetOracleDatabaseSchemas(entry agentmodel.OratabEntry) []model.OracleDatabaseSchema {
-	out := lf.execute("schema", entry.DBName, entry.OracleHome)
-	return marshal_oracle.Schemas(out)
+func (lf *LinuxFetcherImpl) GetOracleDatabaseSchemas(entry agentmodel.OratabEntry) (schemas []model.OracleDatabaseSchema, err error) {
+	out, err := lf.execute("schema", entry.DBName, entry.OracleHome)
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("schema", entry.DBName, &err)
+
+	return marshal_oracle.Schemas(out), nil
 }
 
 // GetOracleDatabasePatches get
-func (lf *LinuxFetcherImpl) GetOracleDatabasePatches(entry agentmodel.OratabEntry, dbVersion string) []model.OracleDatabasePatch {
-	out := lf.execute("patch", entry.DBName, dbVersion, entry.OracleHome)
-	return marshal_oracle.Patches(out)
+func (lf *LinuxFetcherImpl) GetOracleDatabasePatches(entry agentmodel.OratabEntry, dbVersion string) (patches []model.OracleDatabasePatch, err error) {
+	out, err := lf.execute("patch", entry.DBName, dbVersion, entry.OracleHome)
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("patch", entry.DBName, &err)
+
+	return marshal_oracle.Patches(out), nil
 }
 
 // GetOracleDatabaseFeatureUsageStat get
-func (lf *LinuxFetcherImpl) GetOracleDatabaseFeatureUsageStat(entry agentmodel.OratabEntry, dbVersion string) []model.OracleDatabaseFeatureUsageStat {
-	out := lf.execute("opt", entry.DBName, dbVersion, entry.OracleHome)
-	return marshal_oracle.DatabaseFeatureUsageStat(out)
+func (lf *LinuxFetcherImpl) GetOracleDatabaseFeatureUsageStat(entry agentmodel.OratabEntry, dbVersion string) (stats []model.OracleDatabaseFeatureUsageStat, err error) {
+	out, err := lf.execute("opt", entry.DBName, dbVersion, entry.OracleHome)
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("opt", entry.DBName, &err)
+
+	return marshal_oracle.DatabaseFeatureUsageStat(out), nil
 }
 
 // GetOracleDatabaseLicenses get
-func (lf *LinuxFetcherImpl) GetOracleDatabaseLicenses(entry agentmodel.OratabEntry, dbVersion, hardwareAbstractionTechnology string) []model.OracleDatabaseLicense {
-	out := lf.execute("license", entry.DBName, dbVersion, hardwareAbstractionTechnology, entry.OracleHome)
-	return marshal_oracle.Licenses(out)
+func (lf *LinuxFetcherImpl) GetOracleDatabaseLicenses(entry agentmodel.OratabEntry, dbVersion, hardwareAbstractionTechnology string) (licenses []model.OracleDatabaseLicense, err error) {
+	out, err := lf.execute("license", entry.DBName, dbVersion, hardwareAbstractionTechnology, entry.OracleHome)
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("license", entry.DBName, &err)
+
+	return marshal_oracle.Licenses(out), nil
 }
 
 // GetOracleDatabaseADDMs get
-func (lf *LinuxFetcherImpl) GetOracleDatabaseADDMs(entry agentmodel.OratabEntry) []model.OracleDatabaseAddm {
-	out := lf.execute("addm", entry.DBName, entry.OracleHome)
-	return marshal_oracle.Addms(out)
+func (lf *LinuxFetcherImpl) GetOracleDatabaseADDMs(entry agentmodel.OratabEntry) (addms []model.OracleDatabaseAddm, err error) {
+	out, err := lf.execute("addm", entry.DBName, entry.OracleHome)
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("addm", entry.DBName, &err)
+
+	return marshal_oracle.Addms(out), nil
 }
 
 // GetOracleDatabaseSegmentAdvisors get
-func (lf *LinuxFetcherImpl) GetOracleDatabaseSegmentAdvisors(entry agentmodel.OratabEntry) []model.OracleDatabaseSegmentAdvisor {
-	out := lf.execute("segmentadvisor", entry.DBName, entry.OracleHome)
-	return marshal_oracle.SegmentAdvisor(out)
+func (lf *LinuxFetcherImpl) GetOracleDatabaseSegmentAdvisors(entry agentmodel.OratabEntry) (segmentAdvisors []model.OracleDatabaseSegmentAdvisor, err error) {
+	out, err := lf.execute("segmentadvisor", entry.DBName, entry.OracleHome)
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("segmentadvisor", entry.DBName, &err)
+
+	return marshal_oracle.SegmentAdvisor(out), nil
 }
 
 // GetOracleDatabasePSUs get
-func (lf *LinuxFetcherImpl) GetOracleDatabasePSUs(entry agentmodel.OratabEntry, dbVersion string) []model.OracleDatabasePSU {
-	out := lf.execute("psu", entry.DBName, dbVersion, entry.OracleHome)
-	return marshal_oracle.PSU(out)
+func (lf *LinuxFetcherImpl) GetOracleDatabasePSUs(entry agentmodel.OratabEntry, dbVersion string) (psus []model.OracleDatabasePSU, err error) {
+	out, err := lf.execute("psu", entry.DBName, dbVersion, entry.OracleHome)
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("psu", entry.DBName, &err)
+
+	return marshal_oracle.PSU(out), nil
 }
 
 // GetOracleDatabaseBackups get
-func (lf *LinuxFetcherImpl) GetOracleDatabaseBackups(entry agentmodel.OratabEntry) []model.OracleDatabaseBackup {
-	out := lf.execute("backup", entry.DBName, entry.OracleHome)
-	return marshal_oracle.Backups(out)
+func (lf *LinuxFetcherImpl) GetOracleDatabaseBackups(entry agentmodel.OratabEntry) (backups []model.OracleDatabaseBackup, err error) {
+	out, err := lf.execute("backup", entry.DBName, entry.OracleHome)
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("backup", entry.DBName, &err)
+
+	return marshal_oracle.Backups(out), nil
+}
+
+// GetOracleDatabaseBackupHistory get the RMAN jobs of the last BackupHistoryDays and the last successful backups
+func (lf *LinuxFetcherImpl) GetOracleDatabaseBackupHistory(entry agentmodel.OratabEntry) (jobs []model.OracleDatabaseBackupJob, lastBackups model.OracleDatabaseLastBackups, err error) {
+	days := strconv.Itoa(int(lf.configuration.Features.OracleDatabase.BackupHistoryDays))
+	out, err := lf.execute("backup_history", entry.DBName, entry.OracleHome, days)
+	if err != nil {
+		return nil, model.OracleDatabaseLastBackups{}, err
+	}
+
+	defer recoverMarshal("backup_history", entry.DBName, &err)
+
+	jobs, lastBackups = marshal_oracle.BackupHistory(out)
+
+	return jobs, lastBackups, nil
+}
+
+// GetOracleDatabaseInstances get whether the database is a cluster database and its running instances
+func (lf *LinuxFetcherImpl) GetOracleDatabaseInstances(entry agentmodel.OratabEntry) (clusterDatabase bool, instances []model.OracleDatabaseInstance, err error) {
+	out, err := lf.execute("instances", entry.DBName, entry.OracleHome)
+	if err != nil {
+		return false, nil, err
+	}
+
+	defer recoverMarshal("instances", entry.DBName, &err)
+
+	clusterDatabase, instances = marshal_oracle.Instances(out)
+
+	return clusterDatabase, instances, nil
+}
+
+// GetOracleDatabaseDataguard get the Data Guard status, available also on mounted databases
+func (lf *LinuxFetcherImpl) GetOracleDatabaseDataguard(entry agentmodel.OratabEntry) (status *model.OracleDatabaseDataguardStatus, err error) {
+	out, err := lf.execute("dataguard", entry.DBName, entry.OracleHome)
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("dataguard", entry.DBName, &err)
+
+	return marshal_oracle.Dataguard(out), nil
 }
 
 // GetOracleDatabaseCheckPDB get
-func (lf *LinuxFetcherImpl) GetOracleDatabaseCheckPDB(entry agentmodel.OratabEntry) bool {
-	out := lf.execute("checkpdb", entry.DBName, entry.OracleHome)
-	return strings.TrimSpace(string(out)) == "TRUE"
+func (lf *LinuxFetcherImpl) GetOracleDatabaseCheckPDB(entry agentmodel.OratabEntry) (bool, error) {
+	out, err := lf.execute("checkpdb", entry.DBName, entry.OracleHome)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.TrimSpace(string(out)) == "TRUE", nil
 }
 
 // GetOracleDatabasePDBs get
-func (lf *LinuxFetcherImpl) GetOracleDatabasePDBs(entry agentmodel.OratabEntry) []model.OracleDatabasePluggableDatabase {
-	out := lf.execute("listpdb", entry.DBName, entry.OracleHome)
-	return marshal_oracle.ListPDB(out)
+func (lf *LinuxFetcherImpl) GetOracleDatabasePDBs(entry agentmodel.OratabEntry) (pdbs []model.OracleDatabasePluggableDatabase, err error) {
+	out, err := lf.execute("listpdb", entry.DBName, entry.OracleHome)
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("listpdb", entry.DBName, &err)
+
+	return marshal_oracle.ListPDB(out), nil
 }
 
 // GetOracleDatabasePDBTablespaces get
-func (lf *LinuxFetcherImpl) GetOracleDatabasePDBTablespaces(entry agentmodel.OratabEntry, pdb string) []model.OracleDatabaseTablespace {
-	out := lf.execute("tablespace_pdb", entry.DBName, entry.OracleHome, pdb)
-	return marshal_oracle.Tablespaces(out)
+func (lf *LinuxFetcherImpl) GetOracleDatabasePDBTablespaces(entry agentmodel.OratabEntry, pdb string) (tablespaces []model.OracleDatabaseTablespace, err error) {
+	out, err := lf.execute("tablespace_pdb", entry.DBName, entry.OracleHome, pdb)
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("tablespace_pdb", entry.DBName, &err)
+
+	return marshal_oracle.Tablespaces(out), nil
 }
 
 // GetOracleDatabasePDBSchemas get
-func (lf *LinuxFetcherImpl) GetOracleDatabasePDBSchemas(entry agentmodel.OratabEntry, pdb string) []model.OracleDatabaseSchema {
-	out := lf.execute("schema_pdb", entry.DBName, entry.OracleHome, pdb)
-	return marshal_oracle.Schemas(out)
+func (lf *LinuxFetcherImpl) GetOracleDatabasePDBSchemas(entry agentmodel.OratabEntry, pdb string) (schemas []model.OracleDatabaseSchema, err error) {
+	out, err := lf.execute("schema_pdb", entry.DBName, entry.OracleHome, pdb)
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("schema_pdb", entry.DBName, &err)
+
+	return marshal_oracle.Schemas(out), nil
+}
+
+// GetOracleDatabasePDBServices get
+func (lf *LinuxFetcherImpl) GetOracleDatabasePDBServices(entry agentmodel.OratabEntry, pdb string) (services []model.OracleDatabaseService, err error) {
+	out, err := lf.execute("service_pdb", entry.DBName, entry.OracleHome, pdb)
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("service_pdb", entry.DBName, &err)
+
+	return marshal_oracle.Services(out), nil
+}
+
+// GetOracleDatabasePDBParameters get
+func (lf *LinuxFetcherImpl) GetOracleDatabasePDBParameters(entry agentmodel.OratabEntry, pdb string) (parameters []model.OracleDatabaseParameter, err error) {
+	out, err := lf.execute("parameters_pdb", entry.DBName, entry.OracleHome, pdb)
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("parameters_pdb", entry.DBName, &err)
+
+	return marshal_oracle.Parameters(out), nil
+}
+
+// GetOracleDatabaseParameters get the initialization parameters of the local instance
+func (lf *LinuxFetcherImpl) GetOracleDatabaseParameters(entry agentmodel.OratabEntry) (parameters []model.OracleDatabaseParameter, err error) {
+	out, err := lf.execute("parameters", entry.DBName, entry.OracleHome)
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("parameters", entry.DBName, &err)
+
+	return marshal_oracle.Parameters(out), nil
+}
+
+// GetOracleDatabaseServices get
+func (lf *LinuxFetcherImpl) GetOracleDatabaseServices(entry agentmodel.OratabEntry) (services []model.OracleDatabaseService, err error) {
+	out, err := lf.execute("service", entry.DBName, entry.OracleHome)
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("service", entry.DBName, &err)
+
+	return marshal_oracle.Services(out), nil
+}
+
+// GetOracleDatabaseClusterwareServices get the services registered in Grid Infrastructure, none if it isn't running
+func (lf *LinuxFetcherImpl) GetOracleDatabaseClusterwareServices(entry agentmodel.OratabEntry, dbUniqueName string) (services []agentmodel.ClusterwareService, err error) {
+	out, err := lf.execute("srvctl_service", entry.DBName, entry.OracleHome, dbUniqueName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("srvctl_service", entry.DBName, &err)
+
+	return marshal_oracle.ClusterwareServices(out), nil
 }
 
 // GetOracleDatabaseTablespaces get
-func (lf *LinuxFetcherImpl) GetOracleDatabasePartitionings(entry agentmodel.OratabEntry) []model.OracleDatabasePartitioning {
-	out := lf.execute("partitioning", entry.DBName, entry.OracleHome)
-	return marshal_oracle.Partitionings(out)
+func (lf *LinuxFetcherImpl) GetOracleDatabasePartitionings(entry agentmodel.OratabEntry) (partitionings []model.OracleDatabasePartitioning, err error) {
+	out, err := lf.execute("partitioning", entry.DBName, entry.OracleHome)
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("partitioning", entry.DBName, &err)
+
+	return marshal_oracle.Partitionings(out), nil
 }
 
 // GetClusters return VMWare clusters from the given hyperVisor
-func (lf *LinuxFetcherImpl) GetClusters(hv config.Hypervisor) []model.ClusterInfo {
+func (lf *LinuxFetcherImpl) GetClusters(hv config.Hypervisor) (clusters []model.ClusterInfo, err error) {
 	var out []byte
 
 	switch hv.Type {
 	case "vmware":
-		out = lf.executePwsh("vmware.ps1", "-s", "cluster", hv.Endpoint, hv.Username, hv.Password)
+		out, err = lf.executePwsh("vmware.ps1", "-s", "cluster", hv.Endpoint, hv.Username, hv.Password)
 
 	case "ovm":
-		out = lf.execute("ovm", "cluster", hv.Endpoint, hv.Username, hv.Password, hv.OvmUserKey, hv.OvmControl)
+		out, err = lf.execute("ovm", "cluster", hv.Endpoint, hv.Username, hv.Password, hv.OvmUserKey, hv.OvmControl)
 
 	default:
-		lf.log.Errorf("Hypervisor not supported: %v (%v)", hv.Type, hv)
-		return make([]model.ClusterInfo, 0)
+		err = fmt.Errorf("Hypervisor not supported: %v (%v)", hv.Type, hv.Endpoint)
+	}
+
+	if err != nil {
+		return nil, err
 	}
 
+	defer recoverMarshal(hv.Type, "", &err)
+
 	fetchedClusters := marshal.Clusters(out)
 	for i := range fetchedClusters {
 		fetchedClusters[i].Type = hv.Type
 		fetchedClusters[i].FetchEndpoint = hv.Endpoint
 	}
 
-	return fetchedClusters
+	return fetchedClusters, nil
 }
 
 // GetVirtualMachines return VMWare virtual machines infos from the given hyperVisor
-func (lf *LinuxFetcherImpl) GetVirtualMachines(hv config.Hypervisor) map[string][]model.VMInfo {
-	var vms map[string][]model.VMInfo
+func (lf *LinuxFetcherImpl) GetVirtualMachines(hv config.Hypervisor) (vms map[string][]model.VMInfo, err error) {
+	defer recoverMarshal(hv.Type, "", &err)
 
 	switch hv.Type {
 	case "vmware":
-		out := lf.executePwsh("vmware.ps1", "-s", "vms", hv.Endpoint, hv.Username, hv.Password)
+		var out []byte
+		if out, err = lf.executePwsh("vmware.ps1", "-s", "vms", hv.Endpoint, hv.Username, hv.Password); err != nil {
+			return nil, err
+		}
+
 		vms = marshal.VmwareVMs(out)
 
 	case "ovm":
-		out := lf.execute("ovm", "vms", hv.Endpoint, hv.Username, hv.Password, hv.OvmUserKey, hv.OvmControl)
+		var out []byte
+		if out, err = lf.execute("ovm", "vms", hv.Endpoint, hv.Username, hv.Password, hv.OvmUserKey, hv.OvmControl); err != nil {
+			return nil, err
+		}
+
 		vms = marshal.OvmVMs(out)
 
 	default:
-		lf.log.Errorf("Hypervisor not supported: %v (%v)", hv.Type, hv)
-		return make(map[string][]model.VMInfo, 0)
+		return nil, fmt.Errorf("Hypervisor not supported: %v (%v)", hv.Type, hv.Endpoint)
 	}
 
 	lf.log.Debugf("Got %d vms from hypervisor: %s", len(vms), hv.Endpoint)
 
-	return vms
+	return vms, nil
+}
+
+// GetOracleASM get the disk groups of the ASM instance in oratab, nil if there's none
+func (lf *LinuxFetcherImpl) GetOracleASM() (asm *model.OracleASMFeature, err error) {
+	out, err := lf.execute("asm", lf.configuration.Features.OracleDatabase.Oratab)
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("asm", "", &err)
+
+	return marshal_oracle.ASM(out), nil
 }
 
 // GetOracleExadataComponents get
-func (lf *LinuxFetcherImpl) GetOracleExadataComponents() []model.OracleExadataComponent {
-	out := lf.execute("exadata/info")
-	return marshal_oracle.ExadataComponent(out)
+func (lf *LinuxFetcherImpl) GetOracleExadataComponents() (components []model.OracleExadataComponent, err error) {
+	out, err := lf.execute("exadata/info")
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("exadata/info", "", &err)
+
+	return marshal_oracle.ExadataComponent(out), nil
 }
 
 // GetOracleExadataCellDisks get
-func (lf *LinuxFetcherImpl) GetOracleExadataCellDisks() map[agentmodel.StorageServerName][]model.OracleExadataCellDisk {
-	out := lf.execute("exadata/storage-status")
-	return marshal_oracle.ExadataCellDisks(out)
+func (lf *LinuxFetcherImpl) GetOracleExadataCellDisks() (cellDisks map[agentmodel.StorageServerName][]model.OracleExadataCellDisk, err error) {
+	out, err := lf.execute("exadata/storage-status")
+	if err != nil {
+		return nil, err
+	}
+
+	defer recoverMarshal("exadata/storage-status", "", &err)
+
+	return marshal_oracle.ExadataCellDisks(out), nil
 }
 
 // GetClustersMembershipStatus get
-func (lf *LinuxFetcherImpl) GetClustersMembershipStatus() model.ClusterMembershipStatus {
-	out := lf.execute("cluster_membership_status")
-	return marshal.ClusterMembershipStatus(out)
+func (lf *LinuxFetcherImpl) GetClustersMembershipStatus() (status model.ClusterMembershipStatus, err error) {
+	out, err := lf.execute("cluster_membership_status")
+	if err != nil {
+		return model.ClusterMembershipStatus{}, err
+	}
+
+	defer recoverMarshal("cluster_membership_status", "", &err)
+
+	return marshal.ClusterMembershipStatus(out), nil
 }
 
 // GetMicrosoftSQLServerInstances get
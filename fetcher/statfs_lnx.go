@@ -0,0 +1,47 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// +build linux
+
+package fetcher
+
+import (
+	"syscall"
+
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+// statfs returns fs with the sizes, in KiB like df -P, and the inodes of the filesystem mounted on fs.MountedOn
+func statfs(fs model.Filesystem) (model.Filesystem, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(fs.MountedOn, &stat); err != nil {
+		return fs, err
+	}
+
+	blockSize := int64(stat.Frsize)
+	if blockSize == 0 {
+		blockSize = int64(stat.Bsize)
+	}
+
+	fs.Size = int64(stat.Blocks) * blockSize / 1024
+	fs.UsedSpace = int64(stat.Blocks-stat.Bfree) * blockSize / 1024
+	fs.AvailableSpace = int64(stat.Bavail) * blockSize / 1024
+
+	fs.Inodes = int64(stat.Files)
+	fs.FreeInodes = int64(stat.Ffree)
+	fs.UsedInodes = fs.Inodes - fs.FreeInodes
+
+	return fs, nil
+}
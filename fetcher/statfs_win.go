@@ -0,0 +1,29 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// +build windows
+
+package fetcher
+
+import (
+	"fmt"
+
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+// statfs isn't supported on windows
+func statfs(fs model.Filesystem) (model.Filesystem, error) {
+	return fs, fmt.Errorf("Not yet implemented for Windows")
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package localserver
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ercole-io/ercole-agent-rhel5/status"
+)
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// metrics write the status of the agent in the Prometheus text format
+func (s *Server) metrics(w http.ResponseWriter, r *http.Request) {
+	snapshot := s.status.Snapshot()
+
+	var buf bytes.Buffer
+
+	writeMetric(&buf, "ercole_agent_info", "gauge", "Version of the agent.",
+		fmt.Sprintf(`{version="%s"}`, labelValueReplacer.Replace(s.version)), 1)
+
+	writeMetric(&buf, "ercole_agent_collections_total", "counter", "Number of collections completed since the agent started.",
+		"", float64(snapshot.Collections))
+	writeMetric(&buf, "ercole_agent_last_collection_timestamp_seconds", "gauge", "Time of the end of the last collection, 0 if none.",
+		"", unixTime(snapshot.LastCollection))
+	writeMetric(&buf, "ercole_agent_last_collection_duration_seconds", "gauge", "Duration of the last collection.",
+		"", snapshot.LastCollectionDuration)
+	writeMetric(&buf, "ercole_agent_databases", "gauge", "Number of databases collected by the last collection.",
+		"", float64(snapshot.Databases))
+
+	writeHeader(&buf, "ercole_agent_fetcher_errors_total", "counter", "Number of errors of each fetcher since the agent started.")
+	names := make([]string, 0, len(snapshot.FetcherErrors))
+	for name := range snapshot.FetcherErrors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		writeSample(&buf, "ercole_agent_fetcher_errors_total",
+			fmt.Sprintf(`{fetcher="%s"}`, labelValueReplacer.Replace(name)), float64(snapshot.FetcherErrors[name]))
+	}
+
+	lastSendSuccess := 0.0
+	if snapshot.LastSendResult == status.SendResultSuccess {
+		lastSendSuccess = 1
+	}
+
+	writeMetric(&buf, "ercole_agent_last_send_timestamp_seconds", "gauge", "Time of the last send of the hostdata, 0 if none.",
+		"", unixTime(snapshot.LastSend))
+	writeMetric(&buf, "ercole_agent_last_send_success", "gauge", "1 if the last send of the hostdata succeeded, 0 otherwise.",
+		"", lastSendSuccess)
+	writeMetric(&buf, "ercole_agent_last_send_http_status", "gauge", "HTTP status of the last send of the hostdata, 0 if no response was received.",
+		"", float64(snapshot.LastSendHTTPStatus))
+
+	writeMetric(&buf, "ercole_agent_next_run_timestamp_seconds", "gauge", "Time of the next scheduled collection.",
+		"", unixTime(snapshot.NextRun))
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		s.log.Debugf("Can't write metrics: %v", err)
+	}
+}
+
+func writeMetric(buf *bytes.Buffer, name, metricType, help, labels string, value float64) {
+	writeHeader(buf, name, metricType, help)
+	writeSample(buf, name, labels, value)
+}
+
+func writeHeader(buf *bytes.Buffer, name, metricType, help string) {
+	fmt.Fprintf(buf, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(buf, "# TYPE %s %s\n", name, metricType)
+}
+
+func writeSample(buf *bytes.Buffer, name, labels string, value float64) {
+	fmt.Fprintf(buf, "%s%s %g\n", name, labels, value)
+}
+
+func unixTime(t time.Time) float64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return float64(t.UnixNano()) / float64(time.Second)
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package localserver implements the local http listener used to monitor the agent
+package localserver
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/ercole-io/ercole-agent-rhel5/config"
+	"github.com/ercole-io/ercole-agent-rhel5/logger"
+	"github.com/ercole-io/ercole-agent-rhel5/scheduler"
+	"github.com/ercole-io/ercole-agent-rhel5/status"
+)
+
+// Server is the local http listener of the agent
+type Server struct {
+	configuration config.Configuration
+	log           logger.Logger
+	version       string
+	status        *status.Status
+	scheduler     *scheduler.Scheduler
+	mux           *http.ServeMux
+}
+
+// NewServer return a Server that exposes the status of the agent
+func NewServer(configuration config.Configuration, log logger.Logger, version string,
+	status *status.Status, scheduler *scheduler.Scheduler) *Server {
+	s := &Server{
+		configuration: configuration,
+		log:           log,
+		version:       version,
+		status:        status,
+		scheduler:     scheduler,
+		mux:           http.NewServeMux(),
+	}
+
+	s.mux.HandleFunc("/metrics", s.metrics)
+	s.mux.HandleFunc("/healthz", s.healthz)
+	s.mux.HandleFunc("/status", s.agentStatus)
+
+	return s
+}
+
+// Start listen on the configured address and serve the requests in background
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.configuration.LocalServer.Address)
+	if err != nil {
+		return err
+	}
+
+	s.log.Infof("Local server listening on [%s]", listener.Addr())
+
+	go func() {
+		if err := http.Serve(listener, s.mux); err != nil {
+			s.log.Errorf("Local server stopped: %v", err)
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package localserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ercole-io/ercole-agent-rhel5/config"
+)
+
+// maxTickDelay is the time after which a scheduler that isn't ticking is considered stuck
+const maxTickDelay = 30 * time.Second
+
+// agentStatusResponse is the body of the /status response
+type agentStatusResponse struct {
+	Version            string               `json:"version"`
+	Configuration      config.Configuration `json:"configuration"`
+	EnabledFeatures    []string             `json:"enabledFeatures"`
+	LastRun            *time.Time           `json:"lastRun"`
+	LastRunDuration    float64              `json:"lastRunDuration"`
+	NextRun            *time.Time           `json:"nextRun"`
+	Databases          int                  `json:"databases"`
+	LastSend           *time.Time           `json:"lastSend"`
+	LastSendResult     string               `json:"lastSendResult"`
+	LastSendHTTPStatus int                  `json:"lastSendHTTPStatus"`
+}
+
+// healthz reply 200 if the agent is alive and its scheduler is ticking.
+// Before the scheduler is started, i.e. during the first collection, the agent is considered healthy.
+func (s *Server) healthz(w http.ResponseWriter, r *http.Request) {
+	lastTick := s.scheduler.LastTick()
+
+	if !lastTick.IsZero() && time.Since(lastTick) > maxTickDelay {
+		http.Error(w, fmt.Sprintf("scheduler isn't ticking since %s", lastTick.Format(time.RFC3339)),
+			http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "OK")
+}
+
+// agentStatus reply with the status of the agent in JSON
+func (s *Server) agentStatus(w http.ResponseWriter, r *http.Request) {
+	snapshot := s.status.Snapshot()
+
+	response := agentStatusResponse{
+		Version:            s.version,
+		Configuration:      s.configuration.Masked(),
+		EnabledFeatures:    enabledFeatures(s.configuration.Features),
+		LastRun:            timeOrNil(snapshot.LastCollection),
+		LastRunDuration:    snapshot.LastCollectionDuration,
+		NextRun:            timeOrNil(snapshot.NextRun),
+		Databases:          snapshot.Databases,
+		LastSend:           timeOrNil(snapshot.LastSend),
+		LastSendResult:     snapshot.LastSendResult,
+		LastSendHTTPStatus: snapshot.LastSendHTTPStatus,
+	}
+
+	body, err := json.MarshalIndent(response, "", "    ")
+	if err != nil {
+		s.log.Errorf("Can't marshal status: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(body); err != nil {
+		s.log.Debugf("Can't write status: %v", err)
+	}
+}
+
+func enabledFeatures(features config.Features) []string {
+	enabled := make([]string, 0)
+
+	if features.OracleDatabase.Enabled {
+		enabled = append(enabled, "OracleDatabase")
+	}
+	if features.Virtualization.Enabled {
+		enabled = append(enabled, "Virtualization")
+	}
+	if features.OracleExadata.Enabled {
+		enabled = append(enabled, "OracleExadata")
+	}
+	if features.OracleASM.Enabled {
+		enabled = append(enabled, "OracleASM")
+	}
+	if features.MicrosoftSQLServer.Enabled {
+		enabled = append(enabled, "MicrosoftSQLServer")
+	}
+
+	return enabled
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
@@ -19,28 +19,50 @@ import (
 	"bytes"
 	"crypto/tls"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/ercole-io/ercole-agent-rhel5/builder"
 	"github.com/ercole-io/ercole-agent-rhel5/config"
+	"github.com/ercole-io/ercole-agent-rhel5/localserver"
 	"github.com/ercole-io/ercole-agent-rhel5/logger"
 	"github.com/ercole-io/ercole-agent-rhel5/model"
 	"github.com/ercole-io/ercole-agent-rhel5/scheduler"
 	"github.com/ercole-io/ercole-agent-rhel5/scheduler/storage"
+	"github.com/ercole-io/ercole-agent-rhel5/status"
+	"github.com/ercole-io/ercole-agent-rhel5/utils"
 )
 
 var version = "latest"
 var hostDataSchemaVersion = 1
 
+// sendDataTimeout is how long the data service has to answer, including the upload of the hostdata
+const sendDataTimeout = 5 * time.Minute
+
 type program struct {
-	log logger.Logger
+	log    logger.Logger
+	status *status.Status
+	force  bool
+
+	// cancel is closed on shutdown to cancel the in-flight collection
+	cancel             chan struct{}
+	lock               sync.Mutex
+	stopping           bool
+	collectionCanceled bool
+	collections        sync.WaitGroup
 }
 
-func (p *program) run() {
+// run the agent and return its exit code. Once the instance lock is taken errors are returned,
+// not fatal, so that the lock is released
+func (p *program) run() int {
 	confLog, err := logger.NewBasicLogger("CONFIG")
 	if err != nil {
 		log.Fatal("Can't initialize CONFIG logger: ", err)
@@ -60,37 +82,92 @@ func (p *program) run() {
 		log.Fatal("Can't initialize AGENT logger: ", err)
 	}
 
-	doBuildAndSend(configuration, p.log)
+	lock, err := utils.LockInstance(p.log, configuration.RunDirectory, p.force)
+	if err != nil {
+		p.log.Fatal("Can't start Ercole agent: ", err)
+	}
+	defer lock.Unlock()
+
+	p.cancel = make(chan struct{})
+	p.status = status.New()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	gracePeriod := time.Duration(configuration.ShutdownGracePeriod) * time.Second
 
 	memStorage := storage.NewMemoryStorage()
 	scheduler := scheduler.New(memStorage)
 
-	_, err = scheduler.RunEvery(time.Duration(configuration.Period)*time.Hour, func() {
-		doBuildAndSend(configuration, p.log)
+	if configuration.LocalServer.Enabled {
+		server := localserver.NewServer(configuration, p.log, version, p.status, &scheduler)
+		if err := server.Start(); err != nil {
+			p.log.Error("Error starting Ercole agent local server: ", err)
+			return exitCodeError
+		}
+	}
+
+	period := time.Duration(configuration.Period) * time.Hour
+
+	firstRun := make(chan struct{})
+	go func() {
+		p.doBuildAndSend(configuration)
+		close(firstRun)
+	}()
+
+	select {
+	case <-firstRun:
+	case sig := <-signals:
+		return p.shutdown(sig, gracePeriod)
+	}
+
+	p.status.SetNextRun(time.Now().Add(period))
+
+	_, err = scheduler.RunEvery(period, func() {
+		p.status.SetNextRun(time.Now().Add(period))
+		p.doBuildAndSend(configuration)
 	})
 	if err != nil {
-		p.log.Fatal("Error scheduling Ercole agent", err)
+		p.log.Error("Error scheduling Ercole agent: ", err)
+		return exitCodeError
 	}
 
 	if err := scheduler.Start(); err != nil {
-		p.log.Fatal("Error starting Ercole agent scheduler", err)
+		p.log.Error("Error starting Ercole agent scheduler: ", err)
+		return exitCodeError
 	}
 
-	scheduler.Wait()
+	sig := <-signals
+	scheduler.Stop()
+
+	return p.shutdown(sig, gracePeriod)
 }
 
-func doBuildAndSend(configuration config.Configuration, log logger.Logger) {
-	hostData := builder.BuildData(configuration, log)
+func (p *program) doBuildAndSend(configuration config.Configuration) {
+	if !p.beginCollection() {
+		return
+	}
+	defer p.collections.Done()
+
+	hostData := builder.BuildData(configuration, p.log, p.cancel)
+	if p.endCollection() {
+		p.log.Warn("Collection canceled by shutdown, hostdata not sent")
+		return
+	}
+
+	p.status.CollectionCompleted(hostData)
 
 	hostData.AgentVersion = version
 	hostData.SchemaVersion = hostDataSchemaVersion
 	hostData.Period = configuration.Period
 	hostData.Tags = []string{}
 
-	sendData(hostData, configuration, log)
+	sendResult, httpStatus := sendData(hostData, configuration, p.log, p.cancel)
+	p.status.SendCompleted(sendResult, httpStatus)
 }
 
-func sendData(data *model.HostData, configuration config.Configuration, log logger.Logger) {
+// sendData return the result of the send and the http status of the response, 0 if none.
+// The request is canceled when cancel is closed
+func sendData(data *model.HostData, configuration config.Configuration, log logger.Logger, cancel <-chan struct{}) (string, int) {
 	log.Info("Sending data...")
 
 	dataBytes, _ := json.Marshal(data)
@@ -100,33 +177,65 @@ func sendData(data *model.HostData, configuration config.Configuration, log logg
 		writeHostDataOnTmpFile(data, log)
 	}
 
-	client := &http.Client{}
-
-	//Disable certificate validation if enableServerValidation is false
-	if configuration.EnableServerValidation == false {
-		client.Transport = &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		}
-	}
+	sendResult := status.SendResultFailed
+	httpStatus := 0
 
 	req, err := http.NewRequest("POST", configuration.DataserviceURL+"/hosts", bytes.NewReader(dataBytes))
+	if err != nil {
+		log.Error("Error sending data: ", err)
+		return sendResult, httpStatus
+	}
 	req.Header.Add("Content-Type", "application/json")
 	req.SetBasicAuth(configuration.AgentUser, configuration.AgentPassword)
-	resp, err := client.Do(req)
 
-	sendResult := "FAILED"
+	// The connections are closed when cancel is closed, which interrupts the request
+	sent := make(chan struct{})
+	defer close(sent)
+	dial := func(network, address string) (net.Conn, error) {
+		conn, err := net.DialTimeout(network, address, sendDataTimeout)
+		if err != nil {
+			return nil, err
+		}
+
+		go func() {
+			select {
+			case <-cancel:
+				conn.Close()
+			case <-sent:
+			}
+		}()
+
+		return conn, nil
+	}
 
+	transport := &http.Transport{
+		Proxy:             http.ProxyFromEnvironment,
+		Dial:              dial,
+		DisableKeepAlives: true,
+	}
+
+	//Disable certificate validation if enableServerValidation is false
+	if configuration.EnableServerValidation == false {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	client := &http.Client{Transport: transport, Timeout: sendDataTimeout}
+
+	resp, err := client.Do(req)
 	if err != nil {
 		log.Error("Error sending data: ", err)
 	} else {
 		log.Info("Response status: ", resp.Status)
+		httpStatus = resp.StatusCode
 		if resp.StatusCode == 200 {
-			sendResult = "SUCCESS"
+			sendResult = status.SendResultSuccess
 		}
 		defer resp.Body.Close()
 	}
 
 	log.Info("Sending result: ", sendResult)
+
+	return sendResult, httpStatus
 }
 
 func writeHostDataOnTmpFile(data *model.HostData, log logger.Logger) {
@@ -151,6 +260,9 @@ func writeHostDataOnTmpFile(data *model.HostData, log logger.Logger) {
 }
 
 func main() {
-	prg := new(program)
-	prg.run()
+	force := flag.Bool("force", false, "Take over a stale lock, left held by another process after the agent which took it exited")
+	flag.Parse()
+
+	prg := &program{force: *force}
+	os.Exit(prg.run())
 }
@@ -27,8 +27,12 @@ import (
 func Clusters(cmdOutput []byte) []model.ClusterInfo {
 	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
 	clusters := []model.ClusterInfo{}
+
+	var line string
+	defer RecoverLine(&line)
+
 	for scanner.Scan() {
-		line := scanner.Text()
+		line = scanner.Text()
 		splitted := strings.Split(line, ",")
 
 		//Check if the line is not the header line
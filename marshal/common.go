@@ -23,6 +23,28 @@ import (
 	"strings"
 )
 
+// LineError is raised when a line of a fetcher output can't be marshalled
+type LineError struct {
+	Line  string
+	Cause interface{}
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("Can't marshal line [%s]: %v", e.Line, e.Cause)
+}
+
+// RecoverLine must be deferred by the marshal functions: it raises again any panic
+// as a LineError with the line that was being marshalled
+func RecoverLine(line *string) {
+	if r := recover(); r != nil {
+		if _, ok := r.(*LineError); ok {
+			panic(r)
+		}
+
+		panic(&LineError{Line: *line, Cause: r})
+	}
+}
+
 func marshalValue(s string) string {
 	if s == "Y" {
 		return "true"
@@ -174,8 +196,11 @@ func parseKeyValueColonSeparated(b []byte) map[string]string {
 
 	data := make(map[string]string, 20)
 
+	var line string
+	defer RecoverLine(&line)
+
 	for scanner.Scan() {
-		line := scanner.Text()
+		line = scanner.Text()
 		splitted := strings.Split(line, ":")
 		key := strings.TrimSpace(splitted[0])
 		value := strings.TrimSpace(splitted[1])
@@ -33,8 +33,11 @@ func Filesystems(cmdOutput []byte) ([]model.Filesystem, error) {
 
 	var err error
 
+	var line string
+	defer RecoverLine(&line)
+
 	for scanner.Scan() {
-		line := scanner.Text()
+		line = scanner.Text()
 		iter := NewIter(strings.Fields(line))
 
 		fs := model.Filesystem{}
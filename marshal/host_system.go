@@ -0,0 +1,293 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package marshal
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+// HostSystemFiles holds the content of the system files which describe the host
+type HostSystemFiles struct {
+	Hostname      string
+	KernelName    string // /proc/sys/kernel/ostype
+	KernelVersion string // /proc/sys/kernel/osrelease
+	CPUInfo       []byte // /proc/cpuinfo
+	MemInfo       []byte // /proc/meminfo
+	OSRelease     []byte // /etc/os-release
+	RedhatRelease []byte // /etc/redhat-release
+	OtherRelease  []byte // any other /etc/*-release
+	// DMI holds the files of /sys/class/dmi/id by name, e.g. sys_vendor
+	DMI map[string]string
+	// HypervisorType is the content of /sys/hypervisor/type
+	HypervisorType string
+	// KernelLog holds the output of dmesg and the content of /var/log/dmesg*,
+	// read only when there is no DMI data
+	KernelLog []byte
+}
+
+// HostFromSystem returns a Host struct from the content of the system files.
+// The values are the same the host fetcher would return, computed correctly.
+func HostFromSystem(files HostSystemFiles) model.Host {
+	var m model.Host
+
+	m.Hostname = strings.TrimSpace(files.Hostname)
+	m.Kernel = strings.TrimSpace(files.KernelName)
+	m.KernelVersion = strings.TrimSpace(files.KernelVersion)
+
+	cpu := parseCPUInfo(files.CPUInfo)
+	if cpu.threads == 0 {
+		panic(fmt.Errorf("No processor found in cpuinfo"))
+	}
+
+	m.CPUModel = cpu.model
+	m.CPUFrequency = cpu.frequency
+	m.CPUThreads = cpu.threads
+	m.CPUCores = cpu.cores
+	m.CPUSockets = cpu.sockets
+	m.ThreadsPerCore = cpu.threads / cpu.cores
+	m.CoresPerSocket = cpu.cores / cpu.sockets
+	if m.CoresPerSocket <= 0 {
+		m.CoresPerSocket = 1
+	}
+
+	memInfo := parseKeyValueColonSeparated(files.MemInfo)
+	m.MemoryTotal = kibToGib(memInfo["MemTotal"])
+	m.SwapTotal = kibToGib(memInfo["SwapTotal"])
+
+	m.OS, m.OSVersion = parseOSRelease(files.OSRelease, files.RedhatRelease, files.OtherRelease)
+
+	m.HardwareAbstractionTechnology = hardwareAbstractionTechnology(files.DMI, files.KernelLog, files.HypervisorType, cpu.hypervisorFlag)
+	if m.HardwareAbstractionTechnology == model.HardwareAbstractionTechnologyPhysical {
+		m.HardwareAbstraction = "PH"
+	} else {
+		m.HardwareAbstraction = "VIRT"
+	}
+
+	return m
+}
+
+type cpuInfo struct {
+	model, frequency        string
+	threads, cores, sockets int
+	hypervisorFlag          bool
+}
+
+// parseCPUInfo counts sockets by physical id and cores by (physical id, core id).
+// When the topology isn't reported, as on some virtual machines, each processor is a core of one socket.
+func parseCPUInfo(content []byte) cpuInfo {
+	var info cpuInfo
+
+	sockets := make(map[string]bool)
+	cores := make(map[string]bool)
+	coresPerSocket := make(map[string]int)
+	hasTopology, hasCoresPerSocket := true, true
+
+	var line string
+	defer RecoverLine(&line)
+
+	processor := make(map[string]string)
+	endProcessor := func() {
+		if len(processor) == 0 {
+			return
+		}
+		defer func() { processor = make(map[string]string) }()
+
+		if _, ok := processor["processor"]; !ok {
+			return
+		}
+		info.threads++
+
+		physicalID, hasPhysicalID := processor["physical id"]
+		coreID, hasCoreID := processor["core id"]
+		cpuCores, hasCPUCores := processor["cpu cores"]
+
+		hasTopology = hasTopology && hasPhysicalID && hasCoreID
+		hasCoresPerSocket = hasCoresPerSocket && hasPhysicalID && hasCPUCores
+
+		sockets[physicalID] = true
+		cores[physicalID+"/"+coreID] = true
+		if hasCPUCores {
+			coresPerSocket[physicalID] = TrimParseInt(cpuCores)
+		}
+
+		if info.threads == 1 {
+			info.model = processor["model name"]
+			info.frequency = processor["cpu MHz"]
+		}
+
+		for _, flag := range strings.Fields(processor["flags"]) {
+			if flag == "hypervisor" {
+				info.hypervisorFlag = true
+			}
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line = scanner.Text()
+
+		if strings.TrimSpace(line) == "" {
+			endProcessor()
+			continue
+		}
+
+		splitted := strings.SplitN(line, ":", 2)
+		if len(splitted) != 2 {
+			continue
+		}
+
+		processor[strings.TrimSpace(splitted[0])] = strings.TrimSpace(splitted[1])
+	}
+	endProcessor()
+
+	if info.threads == 0 {
+		return info
+	}
+
+	switch {
+	case hasTopology:
+		info.sockets = len(sockets)
+		info.cores = len(cores)
+	case hasCoresPerSocket:
+		info.sockets = len(sockets)
+		for _, n := range coresPerSocket {
+			info.cores += n
+		}
+	default:
+		info.sockets = 1
+		info.cores = info.threads
+	}
+
+	if info.cores <= 0 || info.cores > info.threads {
+		info.cores = info.threads
+	}
+
+	// The frequency is in the model name, e.g. "Intel(R) Xeon(R) CPU E5-2690 v4 @ 2.60GHz"
+	if i := strings.Index(info.model, "@"); i >= 0 {
+		info.frequency = strings.TrimSpace(info.model[i+1:])
+		info.model = info.model[:i]
+	} else if info.frequency != "" {
+		info.frequency += "Mhz"
+	}
+	info.model = strings.Join(strings.Fields(info.model), " ")
+
+	return info
+}
+
+// kibToGib converts a /proc/meminfo value, e.g. "16318412 kB", to GiB truncated like the host fetcher does
+func kibToGib(value string) float64 {
+	value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "kB"))
+	if value == "" {
+		return 0
+	}
+
+	return float64(TrimParseInt(value) / 1024 / 1024)
+}
+
+// parseOSRelease returns the OS and its version like the host fetcher
+func parseOSRelease(osRelease, redhatRelease, otherRelease []byte) (os, version string) {
+	osReleaseValues := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(osRelease))
+	for scanner.Scan() {
+		splitted := strings.SplitN(scanner.Text(), "=", 2)
+		if len(splitted) == 2 {
+			osReleaseValues[strings.TrimSpace(splitted[0])] = strings.Trim(strings.TrimSpace(splitted[1]), `"'`)
+		}
+	}
+
+	// e.g. "Red Hat Enterprise Linux Server release 5.11 (Tikanga)"
+	firstLine := func(content []byte) string {
+		return strings.TrimSpace(strings.SplitN(string(content), "\n", 2)[0])
+	}
+	releaseVersion := func(line string) string {
+		if i := strings.Index(line, " release "); i >= 0 {
+			if fields := strings.Fields(line[i+len(" release "):]); len(fields) > 0 {
+				return fields[0]
+			}
+		}
+
+		return ""
+	}
+
+	switch {
+	case len(redhatRelease) > 0:
+		os = "Red Hat Enterprise Linux"
+	case osReleaseValues["NAME"] != "":
+		os = osReleaseValues["NAME"]
+	case len(otherRelease) > 0:
+		os = firstLine(otherRelease)
+		if i := strings.Index(os, " release "); i >= 0 {
+			os = os[:i]
+		}
+	}
+
+	switch {
+	case osReleaseValues["VERSION_ID"] != "":
+		version = osReleaseValues["VERSION_ID"]
+	case len(redhatRelease) > 0:
+		version = releaseVersion(firstLine(redhatRelease))
+	case len(otherRelease) > 0:
+		version = releaseVersion(firstLine(otherRelease))
+	}
+
+	if os == "" {
+		os = "unknown"
+	}
+	if version == "" {
+		version = "unknown"
+	}
+
+	return os, version
+}
+
+// hardwareAbstractionTechnology detects the hypervisor from the DMI data or, on kernels without DMI in /sys
+// like RHEL5 ones, from the kernel log messages checked by the host fetcher
+func hardwareAbstractionTechnology(dmi map[string]string, kernelLog []byte, hypervisorType string, hypervisorFlag bool) string {
+	vendor := strings.ToLower(dmi["sys_vendor"])
+	product := strings.ToLower(dmi["product_name"])
+	bios := strings.ToLower(dmi["bios_version"])
+	log := string(kernelLog)
+
+	switch {
+	case strings.Contains(bios, "ovm") || strings.Contains(product, "ovm"):
+		return model.HardwareAbstractionTechnologyOvm
+	case strings.Contains(vendor, "vmware"):
+		return model.HardwareAbstractionTechnologyVmware
+	case strings.Contains(vendor, "microsoft") && strings.Contains(product, "virtual machine"):
+		return model.HardwareAbstractionTechnologyHyperv
+	case strings.Contains(product, "kvm") || strings.Contains(vendor, "qemu"):
+		return model.HardwareAbstractionTechnologyKvm
+	case strings.Contains(log, "OVM"):
+		return model.HardwareAbstractionTechnologyOvm
+	case strings.Contains(log, "VMware"):
+		return model.HardwareAbstractionTechnologyVmware
+	case strings.Contains(log, "HyperV"):
+		return model.HardwareAbstractionTechnologyHyperv
+	case strings.Contains(strings.ToLower(log), "hypervisor detected: kvm"):
+		return model.HardwareAbstractionTechnologyKvm
+	// The host fetcher never reported XEN and the license calculation doesn't handle it
+	case strings.Contains(vendor, "xen") || strings.TrimSpace(hypervisorType) == "xen" || hypervisorFlag:
+		return model.HardwareAbstractionTechnologyVmother
+	default:
+		return model.HardwareAbstractionTechnologyPhysical
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package marshal
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+// cpuinfoProcessor returns the block of /proc/cpuinfo of a processor, without the topology fields left empty
+func cpuinfoProcessor(processor int, physicalID, coreID, cpuCores, flags string) string {
+	lines := []string{
+		fmt.Sprintf("processor\t: %d", processor),
+		"vendor_id\t: GenuineIntel",
+		"model name\t: Intel(R) Xeon(R) CPU E5-2690 v4 @ 2.60GHz",
+		"cpu MHz\t\t: 2599.998",
+	}
+	if physicalID != "" {
+		lines = append(lines, "physical id\t: "+physicalID)
+	}
+	if cpuCores != "" {
+		lines = append(lines, "cpu cores\t: "+cpuCores)
+	}
+	if coreID != "" {
+		lines = append(lines, "core id\t\t: "+coreID)
+	}
+	lines = append(lines, "flags\t\t: fpu vme de pse tsc msr pae"+flags)
+
+	return strings.Join(lines, "\n") + "\n\n"
+}
+
+func TestParseCPUInfo(t *testing.T) {
+	twoSocketsHT := ""
+	for i := 0; i < 8; i++ {
+		// Linux numbers the siblings after all the cores
+		twoSocketsHT += cpuinfoProcessor(i, fmt.Sprint(i/2%2), fmt.Sprint(i%2), "2", "")
+	}
+
+	noCoreID := ""
+	for i := 0; i < 4; i++ {
+		noCoreID += cpuinfoProcessor(i, fmt.Sprint(i/2), "", "1", "")
+	}
+
+	// RHEL5 guests often report neither physical id nor core id
+	noTopology := ""
+	for i := 0; i < 4; i++ {
+		noTopology += cpuinfoProcessor(i, "", "", "", " hypervisor")
+	}
+
+	oldXeon := "processor\t: 0\nmodel name\t: Dual Core AMD Opteron(tm) Processor 280\ncpu MHz\t\t: 2393.000\n" +
+		"physical id\t: 0\ncore id\t\t: 0\ncpu cores\t: 1\nflags\t\t: fpu\n"
+
+	testCases := []struct {
+		name    string
+		cpuinfo string
+		want    cpuInfo
+	}{
+		{
+			name:    "two sockets with hyperthreading",
+			cpuinfo: twoSocketsHT,
+			want: cpuInfo{model: "Intel(R) Xeon(R) CPU E5-2690 v4", frequency: "2.60GHz",
+				threads: 8, cores: 4, sockets: 2},
+		},
+		{
+			name:    "cpu cores without core id",
+			cpuinfo: noCoreID,
+			want: cpuInfo{model: "Intel(R) Xeon(R) CPU E5-2690 v4", frequency: "2.60GHz",
+				threads: 4, cores: 2, sockets: 2},
+		},
+		{
+			name:    "no topology",
+			cpuinfo: noTopology,
+			want: cpuInfo{model: "Intel(R) Xeon(R) CPU E5-2690 v4", frequency: "2.60GHz",
+				threads: 4, cores: 4, sockets: 1, hypervisorFlag: true},
+		},
+		{
+			name:    "frequency not in the model name, without trailing blank line",
+			cpuinfo: oldXeon,
+			want: cpuInfo{model: "Dual Core AMD Opteron(tm) Processor 280", frequency: "2393.000Mhz",
+				threads: 1, cores: 1, sockets: 1},
+		},
+		{
+			name:    "empty",
+			cpuinfo: "",
+			want:    cpuInfo{},
+		},
+	}
+
+	for _, tc := range testCases {
+		if got := parseCPUInfo([]byte(tc.cpuinfo)); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %+v, want %+v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestHostFromSystem(t *testing.T) {
+	cpuinfo := ""
+	for i := 0; i < 4; i++ {
+		cpuinfo += cpuinfoProcessor(i, "0", fmt.Sprint(i%2), "2", " hypervisor")
+	}
+
+	files := HostSystemFiles{
+		Hostname:      "dbhost01",
+		KernelName:    "Linux",
+		KernelVersion: "2.6.18-419.el5",
+		CPUInfo:       []byte(cpuinfo),
+		MemInfo:       []byte("MemTotal:       16318412 kB\nMemFree:         1253324 kB\nSwapTotal:       8388604 kB\n"),
+		RedhatRelease: []byte("Red Hat Enterprise Linux Server release 5.11 (Tikanga)\n"),
+		DMI:           map[string]string{},
+		KernelLog:     []byte("Linux version 2.6.18-419.el5\nDMI 2.4 present.\nVMware vmxnet3 virtual NIC driver - version 1.1.18.0\n"),
+	}
+
+	want := model.Host{
+		Hostname:                      "dbhost01",
+		CPUModel:                      "Intel(R) Xeon(R) CPU E5-2690 v4",
+		CPUFrequency:                  "2.60GHz",
+		CPUSockets:                    1,
+		CPUCores:                      2,
+		CPUThreads:                    4,
+		ThreadsPerCore:                2,
+		CoresPerSocket:                2,
+		HardwareAbstraction:           "VIRT",
+		HardwareAbstractionTechnology: model.HardwareAbstractionTechnologyVmware,
+		Kernel:                        "Linux",
+		KernelVersion:                 "2.6.18-419.el5",
+		OS:                            "Red Hat Enterprise Linux",
+		OSVersion:                     "5.11",
+		MemoryTotal:                   15,
+		SwapTotal:                     7,
+	}
+
+	if got := HostFromSystem(files); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestHardwareAbstractionTechnology(t *testing.T) {
+	testCases := []struct {
+		name           string
+		dmi            map[string]string
+		kernelLog      string
+		hypervisorType string
+		hypervisorFlag bool
+		want           string
+	}{
+		{
+			name: "vmware dmi",
+			dmi:  map[string]string{"sys_vendor": "VMware, Inc.", "product_name": "VMware Virtual Platform"},
+			want: model.HardwareAbstractionTechnologyVmware,
+		},
+		{
+			name: "kvm dmi",
+			dmi:  map[string]string{"sys_vendor": "QEMU", "product_name": "Standard PC (i440FX + PIIX, 1996)"},
+			want: model.HardwareAbstractionTechnologyKvm,
+		},
+		{
+			name: "hyper-v dmi",
+			dmi:  map[string]string{"sys_vendor": "Microsoft Corporation", "product_name": "Virtual Machine"},
+			want: model.HardwareAbstractionTechnologyHyperv,
+		},
+		{
+			name:           "ovm dmi on xen",
+			dmi:            map[string]string{"sys_vendor": "Xen", "product_name": "HVM domU", "bios_version": "4.4.4OVM"},
+			hypervisorType: "xen",
+			want:           model.HardwareAbstractionTechnologyOvm,
+		},
+		{
+			name: "physical dmi",
+			dmi:  map[string]string{"sys_vendor": "HPE", "product_name": "ProLiant DL380 Gen10"},
+			want: model.HardwareAbstractionTechnologyPhysical,
+		},
+		{
+			name:      "kvm kernel log without dmi",
+			dmi:       map[string]string{},
+			kernelLog: "Booting paravirtualized kernel on KVM\nHypervisor detected: KVM\n",
+			want:      model.HardwareAbstractionTechnologyKvm,
+		},
+		{
+			name:      "hyper-v kernel log without dmi",
+			dmi:       map[string]string{},
+			kernelLog: "hv_vmbus: registering driver hv_netvsc\nHyperV Host Build:7601-6.1-17-0.3.\n",
+			want:      model.HardwareAbstractionTechnologyHyperv,
+		},
+		{
+			name:           "ovm kernel log on xen",
+			dmi:            map[string]string{},
+			kernelLog:      "DMI: Xen HVM domU, BIOS 4.4.4OVM 12/15/2020\n",
+			hypervisorType: "xen",
+			want:           model.HardwareAbstractionTechnologyOvm,
+		},
+		{
+			name:           "xen without dmi",
+			dmi:            map[string]string{},
+			hypervisorType: "xen",
+			want:           model.HardwareAbstractionTechnologyVmother,
+		},
+		{
+			name:           "hypervisor flag only",
+			dmi:            map[string]string{},
+			hypervisorFlag: true,
+			want:           model.HardwareAbstractionTechnologyVmother,
+		},
+		{
+			name: "nothing",
+			dmi:  map[string]string{},
+			want: model.HardwareAbstractionTechnologyPhysical,
+		},
+	}
+
+	for _, tc := range testCases {
+		got := hardwareAbstractionTechnology(tc.dmi, []byte(tc.kernelLog), tc.hypervisorType, tc.hypervisorFlag)
+		if got != tc.want {
+			t.Errorf("%s: got %s, want %s", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestXenGuestLicenses(t *testing.T) {
+	cpuinfo := ""
+	for i := 0; i < 4; i++ {
+		cpuinfo += cpuinfoProcessor(i, "0", fmt.Sprint(i), "4", "")
+	}
+
+	host := HostFromSystem(HostSystemFiles{
+		Hostname:       "xenguest01",
+		KernelName:     "Linux",
+		KernelVersion:  "2.6.18-419.el5xen",
+		CPUInfo:        []byte(cpuinfo),
+		MemInfo:        []byte("MemTotal:       8155176 kB\nSwapTotal:      4194300 kB\n"),
+		RedhatRelease:  []byte("Red Hat Enterprise Linux Server release 5.11 (Tikanga)\n"),
+		DMI:            map[string]string{"sys_vendor": "Xen", "product_name": "HVM domU"},
+		HypervisorType: "xen",
+	})
+
+	if host.HardwareAbstractionTechnology != model.HardwareAbstractionTechnologyVmother || host.HardwareAbstraction != "VIRT" {
+		t.Fatalf("got %s/%s, want VIRT/VMOTHER", host.HardwareAbstraction, host.HardwareAbstractionTechnology)
+	}
+
+	testCases := []struct {
+		version string
+		want    float64
+	}{
+		{"Oracle Database 11g Enterprise Edition Release 11.2.0.4.0 - 64bit Production", 0.5},
+		{"Oracle Database 11g Release 11.2.0.4.0 - 64bit Production", 0},
+	}
+
+	for _, tc := range testCases {
+		database := model.OracleDatabase{Version: tc.version}
+		if got := database.CoreFactor(host); got != tc.want {
+			t.Errorf("%s: got core factor %v, want %v", tc.version, got, tc.want)
+		}
+	}
+}
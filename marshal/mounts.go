@@ -0,0 +1,129 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package marshal
+
+import (
+	"bufio"
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+// pseudoFilesystemTypes are the types of the filesystems without storage, which aren't collected
+var pseudoFilesystemTypes = map[string]bool{
+	"autofs":      true,
+	"binfmt_misc": true,
+	"bpf":         true,
+	"cgroup":      true,
+	"cgroup2":     true,
+	"configfs":    true,
+	"debugfs":     true,
+	"devpts":      true,
+	"efivarfs":    true,
+	"fusectl":     true,
+	"hugetlbfs":   true,
+	"mqueue":      true,
+	"nfsd":        true,
+	"nsfs":        true,
+	"proc":        true,
+	"pstore":      true,
+	"rootfs":      true,
+	"rpc_pipefs":  true,
+	"securityfs":  true,
+	"selinuxfs":   true,
+	"sysfs":       true,
+	"tracefs":     true,
+	"usbfs":       true,
+}
+
+// Mounts returns the filesystems mounted according to /proc/mounts, sorted by mountpoint, without sizes.
+// Pseudo filesystems are skipped, and for each mountpoint only the last mount, the visible one, is kept.
+func Mounts(procMounts []byte) []model.Filesystem {
+	byMountpoint := make(map[string]model.Filesystem)
+
+	scanner := bufio.NewScanner(bytes.NewReader(procMounts))
+
+	var line string
+	defer RecoverLine(&line)
+
+	for scanner.Scan() {
+		line = scanner.Text()
+
+		// device mountpoint type options dump pass
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+
+		if pseudoFilesystemTypes[fields[2]] {
+			continue
+		}
+
+		fs := model.Filesystem{
+			Filesystem:   unescapeMountField(fields[0]),
+			MountedOn:    unescapeMountField(fields[1]),
+			Type:         fields[2],
+			MountOptions: fields[3],
+		}
+
+		for _, option := range strings.Split(fs.MountOptions, ",") {
+			if option == "ro" {
+				fs.ReadOnly = true
+			}
+		}
+
+		byMountpoint[fs.MountedOn] = fs
+	}
+
+	filesystems := make([]model.Filesystem, 0, len(byMountpoint))
+	for _, fs := range byMountpoint {
+		filesystems = append(filesystems, fs)
+	}
+	sort.Sort(byMountedOn(filesystems))
+
+	return filesystems
+}
+
+// unescapeMountField decodes the octal escapes, e.g. \040 for space, of the fields of /proc/mounts
+func unescapeMountField(field string) string {
+	if !strings.Contains(field, `\`) {
+		return field
+	}
+
+	var buf bytes.Buffer
+	for i := 0; i < len(field); i++ {
+		if field[i] == '\\' && i+3 < len(field) {
+			if c, err := strconv.ParseUint(field[i+1:i+4], 8, 8); err == nil {
+				buf.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+
+		buf.WriteByte(field[i])
+	}
+
+	return buf.String()
+}
+
+type byMountedOn []model.Filesystem
+
+func (s byMountedOn) Len() int           { return len(s) }
+func (s byMountedOn) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byMountedOn) Less(i, j int) bool { return s[i].MountedOn < s[j].MountedOn }
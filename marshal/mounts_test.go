@@ -0,0 +1,73 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package marshal
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+func TestMounts(t *testing.T) {
+	procMounts := `rootfs / rootfs rw 0 0
+/dev/root / ext3 rw,data=ordered 0 0
+/proc /proc proc rw 0 0
+/sys /sys sysfs rw 0 0
+devpts /dev/pts devpts rw 0 0
+/dev/sda1 /boot ext3 rw,data=ordered 0 0
+tmpfs /dev/shm tmpfs rw 0 0
+/dev/mapper/vg01-u01 /u01 ext4 rw,relatime,barrier=1,data=ordered 0 0
+nas01:/export/backup /mnt/rman\040backup nfs ro,vers=3,hard,intr,addr=10.0.0.5 0 0
+/dev/sdb1 /mnt/tab\011dir ext4 rw 0 0
+/dev/sdc1 /u01 xfs rw,noatime 0 0
+`
+
+	want := []model.Filesystem{
+		{Filesystem: "/dev/root", Type: "ext3", MountedOn: "/", MountOptions: "rw,data=ordered"},
+		{Filesystem: "/dev/sda1", Type: "ext3", MountedOn: "/boot", MountOptions: "rw,data=ordered"},
+		{Filesystem: "tmpfs", Type: "tmpfs", MountedOn: "/dev/shm", MountOptions: "rw"},
+		{Filesystem: "nas01:/export/backup", Type: "nfs", MountedOn: "/mnt/rman backup",
+			MountOptions: "ro,vers=3,hard,intr,addr=10.0.0.5", ReadOnly: true},
+		{Filesystem: "/dev/sdb1", Type: "ext4", MountedOn: "/mnt/tab\tdir", MountOptions: "rw"},
+		// The last mount over /u01 is the visible one
+		{Filesystem: "/dev/sdc1", Type: "xfs", MountedOn: "/u01", MountOptions: "rw,noatime"},
+	}
+
+	if got := Mounts([]byte(procMounts)); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestUnescapeMountField(t *testing.T) {
+	testCases := []struct {
+		field, want string
+	}{
+		{`/u01/app`, `/u01/app`},
+		{`/mnt/rman\040backup`, `/mnt/rman backup`},
+		{`/mnt/trailing\040`, `/mnt/trailing `},
+		{`/mnt/back\134slash`, `/mnt/back\slash`},
+		{`/mnt/new\012line`, "/mnt/new\nline"},
+		{`/mnt/not\09octal`, `/mnt/not\09octal`},
+		{`/mnt/short\04`, `/mnt/short\04`},
+	}
+
+	for _, tc := range testCases {
+		if got := unescapeMountField(tc.field); got != tc.want {
+			t.Errorf("unescapeMountField(%q) = %q, want %q", tc.field, got, tc.want)
+		}
+	}
+}
@@ -28,9 +28,12 @@ func Addms(cmdOutput []byte) []model.OracleDatabaseAddm {
 	addms := []model.OracleDatabaseAddm{}
 	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
 
+	var line string
+	defer marshal.RecoverLine(&line)
+
 	for scanner.Scan() {
 		addm := new(model.OracleDatabaseAddm)
-		line := scanner.Text()
+		line = scanner.Text()
 		splitted := strings.Split(line, "|||")
 		if len(splitted) == 6 {
 			addm.Finding = strings.TrimSpace(splitted[2])
@@ -0,0 +1,88 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package oracle
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/ercole-io/ercole-agent-rhel5/marshal"
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+// ASM returns information about the ASM disk groups and their disks extracted
+// from the asm fetcher command output, nil if there's no ASM instance.
+func ASM(cmdOutput []byte) *model.OracleASMFeature {
+	var asm *model.OracleASMFeature
+	diskGroups := make(map[int]int)
+
+	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
+
+	var line string
+	defer marshal.RecoverLine(&line)
+
+	for scanner.Scan() {
+		line = scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		splitted := strings.Split(line, "|||")
+		iter := marshal.NewIter(splitted)
+
+		switch strings.TrimSpace(iter()) {
+		case "INSTANCE":
+			asm = &model.OracleASMFeature{
+				InstanceName: strings.TrimSpace(iter()),
+				DiskGroups:   []model.OracleASMDiskGroup{},
+			}
+		case "DISKGROUP":
+			number := marshal.TrimParseInt(iter())
+			diskGroups[number] = len(asm.DiskGroups)
+
+			asm.DiskGroups = append(asm.DiskGroups, model.OracleASMDiskGroup{
+				Name:               strings.TrimSpace(iter()),
+				Redundancy:         strings.TrimSpace(iter()),
+				State:              strings.TrimSpace(iter()),
+				Total:              marshal.TrimParseInt64(iter()),
+				Free:               marshal.TrimParseInt64(iter()),
+				Usable:             marshal.TrimParseInt64(iter()),
+				CompatibilityASM:   strings.TrimSpace(iter()),
+				CompatibilityRDBMS: strings.TrimSpace(iter()),
+				Disks:              []model.OracleASMDisk{},
+			})
+		case "DISK":
+			number := marshal.TrimParseInt(iter())
+			i, ok := diskGroups[number]
+			if !ok {
+				panic(fmt.Errorf("Disk of unknown disk group number [%d]", number))
+			}
+
+			asm.DiskGroups[i].Disks = append(asm.DiskGroups[i].Disks, model.OracleASMDisk{
+				Path:         strings.TrimSpace(iter()),
+				Name:         strings.TrimSpace(iter()),
+				FailGroup:    strings.TrimSpace(iter()),
+				Size:         marshal.TrimParseInt64(iter()),
+				MountStatus:  strings.TrimSpace(iter()),
+				HeaderStatus: strings.TrimSpace(iter()),
+				State:        strings.TrimSpace(iter()),
+			})
+		}
+	}
+
+	return asm
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package oracle
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ercole-io/ercole-agent-rhel5/marshal"
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+func TestASM(t *testing.T) {
+	cmdOutput := `INSTANCE|||+ASM1
+
+DISKGROUP|||         1|||DATA                          |||EXTERN|||MOUNTED    |||    204800|||     81920|||     81920|||19.0.0.0.0                                                  |||11.2.0.2.0
+DISKGROUP|||         2|||RECO                          |||NORMAL|||MOUNTED    |||    102400|||      4096|||     -3072|||19.0.0.0.0                                                  |||19.0.0.0.0
+
+DISK|||         1|||/dev/oracleasm/disks/DATA01                                                      |||DATA_0000                     |||DATA_0000                     |||    102400|||CACHED |||MEMBER      |||NORMAL
+DISK|||         1|||/dev/oracleasm/disks/DATA02                                                      |||DATA_0001                     |||DATA_0001                     |||    102400|||CACHED |||MEMBER      |||NORMAL
+DISK|||         2|||/dev/oracleasm/disks/RECO01                                                      |||RECO_0000                     |||FG1                           |||     51200|||CACHED |||MEMBER      |||NORMAL
+DISK|||         2|||/dev/oracleasm/disks/RECO02                                                      |||RECO_0001                     |||FG2                           |||     51200|||CACHED |||MEMBER      |||NORMAL
+`
+
+	want := &model.OracleASMFeature{
+		InstanceName: "+ASM1",
+		DiskGroups: []model.OracleASMDiskGroup{
+			{
+				Name: "DATA", Redundancy: "EXTERN", State: "MOUNTED", Total: 204800, Free: 81920, Usable: 81920,
+				CompatibilityASM: "19.0.0.0.0", CompatibilityRDBMS: "11.2.0.2.0",
+				Disks: []model.OracleASMDisk{
+					{Path: "/dev/oracleasm/disks/DATA01", Name: "DATA_0000", FailGroup: "DATA_0000", Size: 102400,
+						MountStatus: "CACHED", HeaderStatus: "MEMBER", State: "NORMAL"},
+					{Path: "/dev/oracleasm/disks/DATA02", Name: "DATA_0001", FailGroup: "DATA_0001", Size: 102400,
+						MountStatus: "CACHED", HeaderStatus: "MEMBER", State: "NORMAL"},
+				},
+			},
+			{
+				// usable_file_mb is negative when a failure couldn't be recovered
+				Name: "RECO", Redundancy: "NORMAL", State: "MOUNTED", Total: 102400, Free: 4096, Usable: -3072,
+				CompatibilityASM: "19.0.0.0.0", CompatibilityRDBMS: "19.0.0.0.0",
+				Disks: []model.OracleASMDisk{
+					{Path: "/dev/oracleasm/disks/RECO01", Name: "RECO_0000", FailGroup: "FG1", Size: 51200,
+						MountStatus: "CACHED", HeaderStatus: "MEMBER", State: "NORMAL"},
+					{Path: "/dev/oracleasm/disks/RECO02", Name: "RECO_0001", FailGroup: "FG2", Size: 51200,
+						MountStatus: "CACHED", HeaderStatus: "MEMBER", State: "NORMAL"},
+				},
+			},
+		},
+	}
+
+	if got := ASM([]byte(cmdOutput)); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestASMWithoutInstance(t *testing.T) {
+	if got := ASM([]byte("\n")); got != nil {
+		t.Errorf("got %+v, want nil", got)
+	}
+}
+
+func TestASMDiskOfUnknownDiskGroup(t *testing.T) {
+	defer func() {
+		if _, ok := recover().(*marshal.LineError); !ok {
+			t.Errorf("expected a LineError panic")
+		}
+	}()
+
+	ASM([]byte("INSTANCE|||+ASM\nDISK|||         3|||/dev/sdd|||DATA_0002|||DATA_0002|||100|||CACHED|||MEMBER|||NORMAL\n"))
+}
@@ -20,6 +20,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/ercole-io/ercole-agent-rhel5/marshal"
 	"github.com/ercole-io/ercole-agent-rhel5/model"
 )
 
@@ -29,9 +30,12 @@ func Backups(cmdOutput []byte) []model.OracleDatabaseBackup {
 
 	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
 
+	var line string
+	defer marshal.RecoverLine(&line)
+
 	for scanner.Scan() {
 		backup := new(model.OracleDatabaseBackup)
-		line := scanner.Text()
+		line = scanner.Text()
 		splitted := strings.Split(line, "|||")
 		if len(splitted) == 5 {
 			backup.BackupType = strings.TrimSpace(splitted[0])
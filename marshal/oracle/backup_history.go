@@ -0,0 +1,96 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package oracle
+
+import (
+	"bufio"
+	"strings"
+	"time"
+
+	"github.com/ercole-io/ercole-agent-rhel5/marshal"
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+const backupHistoryTimeLayout = "2006-01-02 15:04:05"
+
+// BackupHistory returns the RMAN jobs and the last successful backups extracted
+// from the backup_history fetcher command output.
+func BackupHistory(cmdOutput []byte) ([]model.OracleDatabaseBackupJob, model.OracleDatabaseLastBackups) {
+	jobs := []model.OracleDatabaseBackupJob{}
+	var lastBackups model.OracleDatabaseLastBackups
+
+	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
+
+	var line string
+	defer marshal.RecoverLine(&line)
+
+	for scanner.Scan() {
+		line = scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		splitted := strings.Split(line, "|||")
+		iter := marshal.NewIter(splitted)
+
+		switch strings.TrimSpace(iter()) {
+		case "JOB":
+			startTime := parseBackupTime(iter())
+			if startTime == nil {
+				panic("Missing job start time")
+			}
+
+			jobs = append(jobs, model.OracleDatabaseBackupJob{
+				StartTime:   *startTime,
+				EndTime:     parseBackupTime(iter()),
+				Status:      strings.TrimSpace(iter()),
+				InputType:   strings.TrimSpace(iter()),
+				InputBytes:  marshal.TrimParseInt64(iter()),
+				OutputBytes: marshal.TrimParseInt64(iter()),
+				DeviceType:  strings.TrimSpace(iter()),
+			})
+		case "LAST":
+			inputType := strings.TrimSpace(iter())
+			endTime := parseBackupTime(iter())
+
+			switch inputType {
+			case "DB FULL":
+				lastBackups.Full = endTime
+			case "DB INCR":
+				lastBackups.Incremental = endTime
+			case "ARCHIVELOG":
+				lastBackups.Archivelog = endTime
+			}
+		}
+	}
+
+	return jobs, lastBackups
+}
+
+// parseBackupTime returns nil if s is empty. The database runs on this host, so it shares its time zone
+func parseBackupTime(s string) *time.Time {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+
+	t, err := time.ParseInLocation(backupHistoryTimeLayout, s, time.Local)
+	if err != nil {
+		panic(err)
+	}
+
+	return &t
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package oracle
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+func localTime(year int, month time.Month, day, hour, min, sec int) *time.Time {
+	t := time.Date(year, month, day, hour, min, sec, 0, time.Local)
+	return &t
+}
+
+func TestBackupHistory(t *testing.T) {
+	// The byte counts are selected with to_char, SQL*Plus would print big numbers in scientific notation
+	cmdOutput := `
+JOB|||2024-05-10 22:00:02|||2024-05-11 01:45:37|||COMPLETED              |||DB FULL      |||107374182400|||32212254720|||SBT_TAPE
+JOB|||2024-05-11 08:00:01|||2024-05-11 08:02:10|||COMPLETED WITH WARNINGS|||ARCHIVELOG   |||5368709120|||5368709120|||DISK
+JOB|||2024-05-11 22:00:03|||                   |||RUNNING                |||DB INCR      |||0|||0|||SBT_TAPE
+
+LAST|||ARCHIVELOG   |||2024-05-11 08:02:10
+LAST|||DB FULL      |||2024-05-11 01:45:37
+`
+
+	wantJobs := []model.OracleDatabaseBackupJob{
+		{StartTime: *localTime(2024, 5, 10, 22, 0, 2), EndTime: localTime(2024, 5, 11, 1, 45, 37), Status: "COMPLETED",
+			InputType: "DB FULL", InputBytes: 107374182400, OutputBytes: 32212254720, DeviceType: "SBT_TAPE"},
+		{StartTime: *localTime(2024, 5, 11, 8, 0, 1), EndTime: localTime(2024, 5, 11, 8, 2, 10), Status: "COMPLETED WITH WARNINGS",
+			InputType: "ARCHIVELOG", InputBytes: 5368709120, OutputBytes: 5368709120, DeviceType: "DISK"},
+		{StartTime: *localTime(2024, 5, 11, 22, 0, 3), Status: "RUNNING", InputType: "DB INCR", DeviceType: "SBT_TAPE"},
+	}
+	wantLastBackups := model.OracleDatabaseLastBackups{
+		Full:       localTime(2024, 5, 11, 1, 45, 37),
+		Archivelog: localTime(2024, 5, 11, 8, 2, 10),
+	}
+
+	jobs, lastBackups := BackupHistory([]byte(cmdOutput))
+	if !reflect.DeepEqual(jobs, wantJobs) {
+		t.Errorf("got jobs %+v, want %+v", jobs, wantJobs)
+	}
+	if !reflect.DeepEqual(lastBackups, wantLastBackups) {
+		t.Errorf("got last backups %+v, want %+v", lastBackups, wantLastBackups)
+	}
+}
+
+func TestBackupHistoryEmpty(t *testing.T) {
+	jobs, lastBackups := BackupHistory([]byte("\n"))
+	if !reflect.DeepEqual(jobs, []model.OracleDatabaseBackupJob{}) {
+		t.Errorf("got jobs %+v, want none", jobs)
+	}
+	if !reflect.DeepEqual(lastBackups, model.OracleDatabaseLastBackups{}) {
+		t.Errorf("got last backups %+v, want none", lastBackups)
+	}
+}
@@ -30,8 +30,11 @@ func Database(cmdOutput []byte) model.OracleDatabase {
 	var db model.OracleDatabase
 	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
 
+	var line string
+	defer marshal.RecoverLine(&line)
+
 	for scanner.Scan() {
-		line := scanner.Text()
+		line = scanner.Text()
 		splitted := strings.Split(line, "|||")
 		if len(splitted) == 27 {
 			iter := marshal.NewIter(splitted)
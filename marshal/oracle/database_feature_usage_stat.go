@@ -30,9 +30,12 @@ func DatabaseFeatureUsageStat(cmdOutput []byte) []model.OracleDatabaseFeatureUsa
 	featuresUsageStats := []model.OracleDatabaseFeatureUsageStat{}
 	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
 
+	var line string
+	defer marshal.RecoverLine(&line)
+
 	for scanner.Scan() {
 		stats := new(model.OracleDatabaseFeatureUsageStat)
-		line := scanner.Text()
+		line = scanner.Text()
 		splitted := strings.Split(line, "|||")
 
 		if len(splitted) == 7 {
@@ -0,0 +1,102 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package oracle
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/ercole-io/ercole-agent-rhel5/marshal"
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+// Dataguard returns the Data Guard status of the database extracted
+// from the dataguard fetcher command output.
+func Dataguard(cmdOutput []byte) *model.OracleDatabaseDataguardStatus {
+	status := &model.OracleDatabaseDataguardStatus{
+		Destinations:         []model.OracleDatabaseDataguardDestination{},
+		LastAppliedSequences: []model.OracleDatabaseDataguardAppliedSequence{},
+	}
+	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
+
+	var line string
+	defer marshal.RecoverLine(&line)
+
+	for scanner.Scan() {
+		line = scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		splitted := strings.Split(line, "|||")
+		iter := marshal.NewIter(splitted)
+
+		switch strings.TrimSpace(iter()) {
+		case "DATABASE":
+			status.Role = strings.TrimSpace(iter())
+			status.ProtectionMode = strings.TrimSpace(iter())
+			status.ProtectionLevel = strings.TrimSpace(iter())
+			status.SwitchoverStatus = strings.TrimSpace(iter())
+			status.BrokerEnabled = marshal.TrimParseBool(iter())
+			status.FastStartFailover = strings.TrimSpace(iter())
+		case "DESTINATION":
+			status.Destinations = append(status.Destinations, model.OracleDatabaseDataguardDestination{
+				ID:           marshal.TrimParseInt(iter()),
+				Destination:  strings.TrimSpace(iter()),
+				DBUniqueName: strings.TrimSpace(iter()),
+				Status:       strings.TrimSpace(iter()),
+				TransmitMode: strings.TrimSpace(iter()),
+				Affirm:       strings.TrimSpace(iter()) == "YES",
+				Error:        strings.TrimSpace(iter()),
+			})
+		case "LAG":
+			name := strings.TrimSpace(iter())
+			lag := parseIntervalSeconds(iter())
+
+			switch name {
+			case "transport lag":
+				status.TransportLag = lag
+			case "apply lag":
+				status.ApplyLag = lag
+			}
+		case "APPLIED":
+			status.LastAppliedSequences = append(status.LastAppliedSequences, model.OracleDatabaseDataguardAppliedSequence{
+				Thread:   marshal.TrimParseInt(iter()),
+				Sequence: marshal.TrimParseInt64(iter()),
+			})
+		}
+	}
+
+	return status
+}
+
+// parseIntervalSeconds parses an interval day to second like "+00 00:01:30", nil if empty
+func parseIntervalSeconds(s string) *int64 {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+
+	var days, hours, minutes, seconds int64
+	if _, err := fmt.Sscanf(strings.TrimPrefix(s, "+"), "%d %d:%d:%d", &days, &hours, &minutes, &seconds); err != nil {
+		panic(fmt.Errorf("Invalid interval [%s]: %v", s, err))
+	}
+
+	total := ((days*24+hours)*60+minutes)*60 + seconds
+
+	return &total
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package oracle
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+func int64Pointer(i int64) *int64 {
+	return &i
+}
+
+func TestDataguard(t *testing.T) {
+	testCases := []struct {
+		name      string
+		cmdOutput string
+		want      *model.OracleDatabaseDataguardStatus
+	}{
+		{
+			name: "primary",
+			cmdOutput: `DATABASE|||PRIMARY         |||MAXIMUM AVAILABILITY|||MAXIMUM AVAILABILITY|||TO STANDBY          |||TRUE|||DISABLED
+
+DESTINATION|||         2|||orclstby                                                    |||ORCLSTBY                      |||VALID    |||PARALLELSYNC|||YES|||
+DESTINATION|||         3|||orclfar                                                     |||ORCLFAR                       |||ERROR    |||ASYNCHRONOUS|||NO |||ORA-12541: TNS:no listener
+`,
+			want: &model.OracleDatabaseDataguardStatus{
+				Role:              "PRIMARY",
+				ProtectionMode:    "MAXIMUM AVAILABILITY",
+				ProtectionLevel:   "MAXIMUM AVAILABILITY",
+				SwitchoverStatus:  "TO STANDBY",
+				BrokerEnabled:     true,
+				FastStartFailover: "DISABLED",
+				Destinations: []model.OracleDatabaseDataguardDestination{
+					{ID: 2, Destination: "orclstby", DBUniqueName: "ORCLSTBY", Status: "VALID",
+						TransmitMode: "PARALLELSYNC", Affirm: true},
+					{ID: 3, Destination: "orclfar", DBUniqueName: "ORCLFAR", Status: "ERROR",
+						TransmitMode: "ASYNCHRONOUS", Error: "ORA-12541: TNS:no listener"},
+				},
+				LastAppliedSequences: []model.OracleDatabaseDataguardAppliedSequence{},
+			},
+		},
+		{
+			name: "physical standby",
+			cmdOutput: `DATABASE|||PHYSICAL STANDBY|||MAXIMUM PERFORMANCE |||MAXIMUM PERFORMANCE |||NOT ALLOWED         |||FALSE|||DISABLED
+LAG|||transport lag                   |||+00 00:00:00
+LAG|||apply lag                       |||+01 02:03:04
+APPLIED|||         1|||     48213
+APPLIED|||         2|||     47109
+`,
+			want: &model.OracleDatabaseDataguardStatus{
+				Role:              "PHYSICAL STANDBY",
+				ProtectionMode:    "MAXIMUM PERFORMANCE",
+				ProtectionLevel:   "MAXIMUM PERFORMANCE",
+				SwitchoverStatus:  "NOT ALLOWED",
+				FastStartFailover: "DISABLED",
+				Destinations:      []model.OracleDatabaseDataguardDestination{},
+				TransportLag:      int64Pointer(0),
+				ApplyLag:          int64Pointer(93784),
+				LastAppliedSequences: []model.OracleDatabaseDataguardAppliedSequence{
+					{Thread: 1, Sequence: 48213},
+					{Thread: 2, Sequence: 47109},
+				},
+			},
+		},
+		{
+			name: "standby without lag values",
+			cmdOutput: `DATABASE|||PHYSICAL STANDBY|||MAXIMUM PERFORMANCE |||MAXIMUM PERFORMANCE |||NOT ALLOWED         |||FALSE|||DISABLED
+LAG|||transport lag                   |||
+LAG|||apply lag                       |||
+`,
+			want: &model.OracleDatabaseDataguardStatus{
+				Role:                 "PHYSICAL STANDBY",
+				ProtectionMode:       "MAXIMUM PERFORMANCE",
+				ProtectionLevel:      "MAXIMUM PERFORMANCE",
+				SwitchoverStatus:     "NOT ALLOWED",
+				FastStartFailover:    "DISABLED",
+				Destinations:         []model.OracleDatabaseDataguardDestination{},
+				LastAppliedSequences: []model.OracleDatabaseDataguardAppliedSequence{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		if got := Dataguard([]byte(tc.cmdOutput)); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %+v, want %+v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestParseIntervalSeconds(t *testing.T) {
+	testCases := []struct {
+		interval string
+		want     *int64
+	}{
+		{"", nil},
+		{"   ", nil},
+		{"+00 00:00:00", int64Pointer(0)},
+		{"+00 00:01:30", int64Pointer(90)},
+		{" +02 10:00:05 ", int64Pointer(208805)},
+	}
+
+	for _, tc := range testCases {
+		got := parseIntervalSeconds(tc.interval)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("parseIntervalSeconds(%q) = %v, want %v", tc.interval, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package oracle
+
+import (
+	"bufio"
+	"strings"
+
+	"github.com/ercole-io/ercole-agent-rhel5/agentmodel"
+	"github.com/ercole-io/ercole-agent-rhel5/marshal"
+)
+
+// RunningHomes marshals the running instances with their ORACLE_HOME, empty if it couldn't be resolved,
+// from the oracle_running_homes fetcher command output
+func RunningHomes(cmdOutput []byte) []agentmodel.OratabEntry {
+	entries := []agentmodel.OratabEntry{}
+	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
+
+	var line string
+	defer marshal.RecoverLine(&line)
+
+	for scanner.Scan() {
+		line = scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		splitted := strings.Split(line, "|||")
+
+		entries = append(entries, agentmodel.OratabEntry{
+			DBName:     strings.TrimSpace(splitted[0]),
+			OracleHome: strings.TrimSpace(splitted[1]),
+		})
+	}
+
+	return entries
+}
+
+// ClusterwareDatabases marshals the instances on hostname of the databases registered in Grid Infrastructure
+// from the oracle_clusterware_databases fetcher command output, the "crsctl stat res -p" attributes
+func ClusterwareDatabases(cmdOutput []byte, hostname string) []agentmodel.OratabEntry {
+	entries := []agentmodel.OratabEntry{}
+	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
+
+	var line string
+	defer marshal.RecoverLine(&line)
+
+	attributes := make(map[string]string)
+	addEntry := func() {
+		if sid := clusterwareInstanceName(attributes, hostname); sid != "" {
+			entries = append(entries, agentmodel.OratabEntry{
+				DBName:     sid,
+				OracleHome: attributes["ORACLE_HOME"],
+			})
+		}
+
+		attributes = make(map[string]string)
+	}
+
+	for scanner.Scan() {
+		line = scanner.Text()
+
+		if strings.TrimSpace(line) == "" {
+			addEntry()
+			continue
+		}
+
+		splitted := strings.SplitN(line, "=", 2)
+		if len(splitted) != 2 {
+			continue
+		}
+
+		attributes[strings.TrimSpace(splitted[0])] = strings.TrimSpace(splitted[1])
+	}
+	addEntry()
+
+	return entries
+}
+
+// clusterwareInstanceName returns the name of the instance on hostname, empty if the database has none there
+func clusterwareInstanceName(attributes map[string]string, hostname string) string {
+	const serverInstancePrefix = "GEN_USR_ORA_INST_NAME@SERVERNAME("
+
+	hasServerInstances := false
+	for key, value := range attributes {
+		if !strings.HasPrefix(key, serverInstancePrefix) || !strings.HasSuffix(key, ")") {
+			continue
+		}
+		hasServerInstances = true
+
+		server := key[len(serverInstancePrefix) : len(key)-1]
+		if shortHostname(server) == shortHostname(hostname) {
+			return value
+		}
+	}
+
+	// The instances of a cluster database are per server, none of them runs on hostname
+	if hasServerInstances || attributes["DATABASE_TYPE"] == "RAC" {
+		return ""
+	}
+
+	// Oracle Restart and RAC One Node
+	if sid := attributes["USR_ORA_INST_NAME"]; sid != "" {
+		return sid
+	}
+
+	return attributes["GEN_USR_ORA_INST_NAME"]
+}
+
+func shortHostname(hostname string) string {
+	return strings.ToLower(strings.SplitN(hostname, ".", 2)[0])
+}
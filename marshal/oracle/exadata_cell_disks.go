@@ -29,9 +29,12 @@ func ExadataCellDisks(cmdOutput []byte) map[agentmodel.StorageServerName][]model
 	cellDisks := make(map[agentmodel.StorageServerName][]model.OracleExadataCellDisk)
 	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
 
+	var line string
+	defer marshal.RecoverLine(&line)
+
 	for scanner.Scan() {
 		cellDisk := new(model.OracleExadataCellDisk)
-		line := scanner.Text()
+		line = scanner.Text()
 		splitted := strings.Split(line, "|||")
 		if len(splitted) == 5 {
 			storageServerName := strings.TrimSpace(splitted[0])
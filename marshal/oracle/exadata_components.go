@@ -28,9 +28,12 @@ func ExadataComponent(cmdOutput []byte) []model.OracleExadataComponent {
 	devices := []model.OracleExadataComponent{}
 	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
 
+	var line string
+	defer marshal.RecoverLine(&line)
+
 	for scanner.Scan() {
 		device := new(model.OracleExadataComponent)
-		line := scanner.Text()
+		line = scanner.Text()
 		splitted := strings.Split(line, "|||")
 		if len(splitted) == 17 {
 			device.Hostname = strings.TrimSpace(splitted[0])
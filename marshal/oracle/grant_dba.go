@@ -20,16 +20,20 @@ import (
 	"bytes"
 	"strings"
 
+	"github.com/ercole-io/ercole-agent-rhel5/marshal"
 	"github.com/ercole-io/ercole-agent-rhel5/model"
-	
 )
 
 func GrantDba(cmdOutput []byte) []model.OracleGrantDba {
 	grants := make([]model.OracleGrantDba, 0)
 
 	scanner := bufio.NewScanner(bytes.NewReader(cmdOutput))
+
+	var line string
+	defer marshal.RecoverLine(&line)
+
 	for scanner.Scan() {
-		line := scanner.Text()
+		line = scanner.Text()
 		grant := model.OracleGrantDba{}
 
 		splitted := strings.Split(line, "|||")
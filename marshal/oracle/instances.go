@@ -0,0 +1,58 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package oracle
+
+import (
+	"bufio"
+	"strings"
+
+	"github.com/ercole-io/ercole-agent-rhel5/marshal"
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+// Instances returns whether the database is a cluster database and its running instances
+// extracted from the instances fetcher command output.
+func Instances(cmdOutput []byte) (bool, []model.OracleDatabaseInstance) {
+	clusterDatabase := false
+	instances := []model.OracleDatabaseInstance{}
+	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
+
+	var line string
+	defer marshal.RecoverLine(&line)
+
+	for scanner.Scan() {
+		line = scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		splitted := strings.Split(line, "|||")
+		iter := marshal.NewIter(splitted)
+
+		instance := model.OracleDatabaseInstance{
+			Number: marshal.TrimParseInt(iter()),
+			Name:   strings.TrimSpace(iter()),
+			Host:   strings.TrimSpace(iter()),
+			Status: strings.TrimSpace(iter()),
+			Local:  marshal.TrimParseBool(iter()),
+		}
+		clusterDatabase = marshal.TrimParseBool(iter())
+
+		instances = append(instances, instance)
+	}
+
+	return clusterDatabase, instances
+}
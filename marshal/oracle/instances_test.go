@@ -0,0 +1,70 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package oracle
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+func TestInstances(t *testing.T) {
+	testCases := []struct {
+		name          string
+		cmdOutput     string
+		wantCluster   bool
+		wantInstances []model.OracleDatabaseInstance
+	}{
+		{
+			name: "rac",
+			cmdOutput: `
+         1|||orcl1           |||dbnode01.example.com                                            |||OPEN        |||N|||TRUE
+         2|||orcl2           |||dbnode02.example.com                                            |||OPEN        |||Y|||TRUE
+
+`,
+			wantCluster: true,
+			wantInstances: []model.OracleDatabaseInstance{
+				{Number: 1, Name: "orcl1", Host: "dbnode01.example.com", Status: "OPEN", Local: false},
+				{Number: 2, Name: "orcl2", Host: "dbnode02.example.com", Status: "OPEN", Local: true},
+			},
+		},
+		{
+			name:        "single instance",
+			cmdOutput:   "         1|||orcl            |||dbhost01                                                        |||MOUNTED     |||Y|||FALSE\n",
+			wantCluster: false,
+			wantInstances: []model.OracleDatabaseInstance{
+				{Number: 1, Name: "orcl", Host: "dbhost01", Status: "MOUNTED", Local: true},
+			},
+		},
+		{
+			name:          "empty",
+			cmdOutput:     "",
+			wantCluster:   false,
+			wantInstances: []model.OracleDatabaseInstance{},
+		},
+	}
+
+	for _, tc := range testCases {
+		cluster, instances := Instances([]byte(tc.cmdOutput))
+		if cluster != tc.wantCluster {
+			t.Errorf("%s: got cluster database %v, want %v", tc.name, cluster, tc.wantCluster)
+		}
+		if !reflect.DeepEqual(instances, tc.wantInstances) {
+			t.Errorf("%s: got %+v, want %+v", tc.name, instances, tc.wantInstances)
+		}
+	}
+}
@@ -29,9 +29,13 @@ func Licenses(cmdOutput []byte) []model.OracleDatabaseLicense {
 	var licenses []model.OracleDatabaseLicense
 
 	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
+
+	var line string
+	defer marshal.RecoverLine(&line)
+
 	for scanner.Scan() {
 		license := new(model.OracleDatabaseLicense)
-		line := scanner.Text()
+		line = scanner.Text()
 		splitted := strings.Split(line, ";")
 
 		if len(splitted) == 3 {
@@ -19,6 +19,7 @@ import (
 	"bufio"
 	"strings"
 
+	"github.com/ercole-io/ercole-agent-rhel5/marshal"
 	"github.com/ercole-io/ercole-agent-rhel5/model"
 )
 
@@ -28,9 +29,12 @@ func ListPDB(cmdOutput []byte) []model.OracleDatabasePluggableDatabase {
 	pdbs := []model.OracleDatabasePluggableDatabase{}
 	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
 
+	var line string
+	defer marshal.RecoverLine(&line)
+
 	for scanner.Scan() {
 		pdb := new(model.OracleDatabasePluggableDatabase)
-		line := scanner.Text()
+		line = scanner.Text()
 		splitted := strings.Split(line, "|||")
 
 		pdb.Name = strings.TrimSpace(splitted[0])
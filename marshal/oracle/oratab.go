@@ -20,6 +20,7 @@ import (
 	"strings"
 
 	"github.com/ercole-io/ercole-agent-rhel5/agentmodel"
+	"github.com/ercole-io/ercole-agent-rhel5/marshal"
 )
 
 // Oratab marshals a list of dbs (one per line) from the oratab command
@@ -28,8 +29,12 @@ func Oratab(cmdOutput []byte) []agentmodel.OratabEntry {
 	var oratab []agentmodel.OratabEntry
 
 	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
+
+	var line string
+	defer marshal.RecoverLine(&line)
+
 	for scanner.Scan() {
-		line := scanner.Text()
+		line = scanner.Text()
 		splitted := strings.Split(line, ":")
 		if len(splitted) < 2 {
 			continue
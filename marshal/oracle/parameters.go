@@ -0,0 +1,94 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package oracle
+
+import (
+	"bufio"
+	"strings"
+
+	"github.com/ercole-io/ercole-agent-rhel5/marshal"
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+// Parameters returns the initialization parameters, with their spfile values, extracted
+// from the parameters fetchers command output.
+func Parameters(cmdOutput []byte) []model.OracleDatabaseParameter {
+	parameters := []model.OracleDatabaseParameter{}
+	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
+
+	var line string
+	defer marshal.RecoverLine(&line)
+
+	instanceName := ""
+	displayValues := make(map[string]string)
+	// spfile values of the parameter by lowercase sid, the values of multi-valued parameters are joined like v$parameter does
+	spfileValues := make(map[string]map[string][]string)
+
+	for scanner.Scan() {
+		line = scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		splitted := strings.Split(line, "|||")
+		iter := marshal.NewIter(splitted)
+
+		switch strings.TrimSpace(iter()) {
+		case "INSTANCE":
+			instanceName = strings.TrimSpace(iter())
+		case "PARAMETER":
+			parameter := model.OracleDatabaseParameter{
+				Name:  strings.TrimSpace(iter()),
+				Value: strings.TrimSpace(iter()),
+			}
+			displayValues[parameter.Name] = strings.TrimSpace(iter())
+			parameter.IsDefault = marshal.TrimParseBool(iter())
+			parameter.IsModified = strings.TrimSpace(iter())
+			parameter.IsBasic = marshal.TrimParseBool(iter())
+
+			parameters = append(parameters, parameter)
+		case "SPFILE":
+			name := strings.TrimSpace(iter())
+			sid := strings.ToLower(strings.TrimSpace(iter()))
+			iter() // ordinal, rows are sorted by it
+			value := strings.TrimSpace(iter())
+
+			if spfileValues[name] == nil {
+				spfileValues[name] = make(map[string][]string)
+			}
+			spfileValues[name][sid] = append(spfileValues[name][sid], value)
+		}
+	}
+
+	for i := range parameters {
+		bySid := spfileValues[parameters[i].Name]
+
+		// A value set for the instance overrides the one set for all of them
+		values, ok := bySid[strings.ToLower(instanceName)]
+		if !ok {
+			values, ok = bySid["*"]
+		}
+		if !ok {
+			continue
+		}
+
+		value := strings.Join(values, ", ")
+		parameters[i].SPFileValue = &value
+		parameters[i].SPFileDiffers = !strings.EqualFold(value, displayValues[parameters[i].Name])
+	}
+
+	return parameters
+}
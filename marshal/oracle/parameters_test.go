@@ -0,0 +1,84 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package oracle
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+func stringPointer(s string) *string {
+	return &s
+}
+
+func TestParameters(t *testing.T) {
+	cmdOutput := `INSTANCE|||orcl2
+
+PARAMETER|||control_files|||/u02/oradata/ORCL/control01.ctl, /u03/fra/ORCL/control02.ctl|||/u02/oradata/ORCL/control01.ctl, /u03/fra/ORCL/control02.ctl|||FALSE|||FALSE    |||TRUE
+PARAMETER|||db_block_size|||8192|||8192|||FALSE|||FALSE    |||TRUE
+PARAMETER|||open_cursors|||300|||300|||TRUE |||FALSE    |||TRUE
+PARAMETER|||processes|||1000|||1000|||FALSE|||FALSE    |||TRUE
+PARAMETER|||sga_target|||4294967296|||4G|||FALSE|||FALSE    |||TRUE
+PARAMETER|||undo_tablespace|||UNDOTBS2|||UNDOTBS2|||FALSE|||FALSE    |||TRUE
+PARAMETER|||db_recovery_file_dest_size|||107374182400|||100G|||FALSE|||SYSTEM_MOD|||FALSE
+
+SPFILE|||control_files|||*|||         1|||/u02/oradata/ORCL/control01.ctl
+SPFILE|||control_files|||*|||         2|||/u03/fra/ORCL/control02.ctl
+SPFILE|||db_block_size|||*|||         1|||8192
+SPFILE|||db_recovery_file_dest_size|||*|||         1|||50G
+SPFILE|||processes|||*|||         1|||1000
+SPFILE|||sga_target|||*|||         1|||2G
+SPFILE|||sga_target|||ORCL2|||         1|||4g
+SPFILE|||undo_tablespace|||orcl1|||         1|||UNDOTBS1
+SPFILE|||undo_tablespace|||orcl2|||         1|||UNDOTBS2
+`
+
+	want := []model.OracleDatabaseParameter{
+		{Name: "control_files", Value: "/u02/oradata/ORCL/control01.ctl, /u03/fra/ORCL/control02.ctl", IsModified: "FALSE", IsBasic: true,
+			SPFileValue: stringPointer("/u02/oradata/ORCL/control01.ctl, /u03/fra/ORCL/control02.ctl")},
+		{Name: "db_block_size", Value: "8192", IsModified: "FALSE", IsBasic: true, SPFileValue: stringPointer("8192")},
+		{Name: "open_cursors", Value: "300", IsDefault: true, IsModified: "FALSE", IsBasic: true},
+		{Name: "processes", Value: "1000", IsModified: "FALSE", IsBasic: true, SPFileValue: stringPointer("1000")},
+		// The value of the instance overrides the one of "*", the sid is matched ignoring the case
+		{Name: "sga_target", Value: "4294967296", IsModified: "FALSE", IsBasic: true, SPFileValue: stringPointer("4g")},
+		// The value of another instance is ignored
+		{Name: "undo_tablespace", Value: "UNDOTBS2", IsModified: "FALSE", IsBasic: true, SPFileValue: stringPointer("UNDOTBS2")},
+		{Name: "db_recovery_file_dest_size", Value: "107374182400", IsModified: "SYSTEM_MOD",
+			SPFileValue: stringPointer("50G"), SPFileDiffers: true},
+	}
+
+	if got := Parameters([]byte(cmdOutput)); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestParametersSpecifiedForOtherInstancesOnly(t *testing.T) {
+	cmdOutput := `INSTANCE|||orcl3
+PARAMETER|||undo_tablespace|||UNDOTBS3|||UNDOTBS3|||FALSE|||FALSE|||TRUE
+SPFILE|||undo_tablespace|||orcl1|||1|||UNDOTBS1
+SPFILE|||undo_tablespace|||orcl2|||1|||UNDOTBS2
+`
+
+	want := []model.OracleDatabaseParameter{
+		{Name: "undo_tablespace", Value: "UNDOTBS3", IsModified: "FALSE", IsBasic: true},
+	}
+
+	if got := Parameters([]byte(cmdOutput)); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
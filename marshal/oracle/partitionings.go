@@ -30,9 +30,12 @@ func Partitionings(cmdOutput []byte) []model.OracleDatabasePartitioning {
 	partitionings := []model.OracleDatabasePartitioning{}
 	scanner := bufio.NewScanner(bytes.NewReader(cmdOutput))
 
+	var line string
+	defer marshal.RecoverLine(&line)
+
 	for scanner.Scan() {
 		partitioning := model.OracleDatabasePartitioning{}
-		line := scanner.Text()
+		line = scanner.Text()
 		splitted := strings.Split(line, "|||")
 		if len(splitted) == 5 {
 			partitioning.Owner = strings.TrimSpace(splitted[0])
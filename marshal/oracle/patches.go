@@ -29,9 +29,12 @@ func Patches(cmdOutput []byte) []model.OracleDatabasePatch {
 	patches := []model.OracleDatabasePatch{}
 	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
 
+	var line string
+	defer marshal.RecoverLine(&line)
+
 	for scanner.Scan() {
 		patch := new(model.OracleDatabasePatch)
-		line := scanner.Text()
+		line = scanner.Text()
 		splitted := strings.Split(line, "|||")
 		if len(splitted) == 9 {
 			patch.Version = strings.TrimSpace(splitted[4])
@@ -19,6 +19,7 @@ import (
 	"bufio"
 	"strings"
 
+	"github.com/ercole-io/ercole-agent-rhel5/marshal"
 	"github.com/ercole-io/ercole-agent-rhel5/model"
 )
 
@@ -27,9 +28,12 @@ func PSU(cmdOutput []byte) []model.OracleDatabasePSU {
 	psuS := []model.OracleDatabasePSU{}
 	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
 
+	var line string
+	defer marshal.RecoverLine(&line)
+
 	for scanner.Scan() {
 		psu := new(model.OracleDatabasePSU)
-		line := scanner.Text()
+		line = scanner.Text()
 		splitted := strings.Split(line, "|||")
 		if len(splitted) == 2 {
 			psu.Description = strings.TrimSpace(splitted[0])
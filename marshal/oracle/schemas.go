@@ -29,11 +29,14 @@ func Schemas(cmdOutput []byte) []model.OracleDatabaseSchema {
 	schemas := []model.OracleDatabaseSchema{}
 	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
 
+	var line string
+	defer marshal.RecoverLine(&line)
+
 	for scanner.Scan() {
 		schema := new(model.OracleDatabaseSchema)
-		line := scanner.Text()
+		line = scanner.Text()
 		splitted := strings.Split(line, "|||")
-		if len(splitted) == 8 {
+		if len(splitted) == 9 {
 			schema.User = strings.TrimSpace(splitted[3])
 			schema.Total = marshal.TrimParseInt(splitted[4])
 			schema.Tables = marshal.TrimParseInt(splitted[5])
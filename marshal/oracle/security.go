@@ -0,0 +1,128 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package oracle
+
+import (
+	"bufio"
+	"strings"
+	"time"
+
+	"github.com/ercole-io/ercole-agent-rhel5/marshal"
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+const (
+	securityTimeLayout   = "2006-01-02 15:04:05"
+	securityTzTimeLayout = "2006-01-02 15:04:05 -07:00"
+)
+
+// Security returns the users, the password profiles and the users with default passwords
+// extracted from the security fetcher command output.
+func Security(cmdOutput []byte) model.OracleDatabaseSecurity {
+	security := model.OracleDatabaseSecurity{
+		Users:                []model.OracleDatabaseUser{},
+		Profiles:             []model.OracleDatabaseProfile{},
+		DefaultPasswordUsers: []string{},
+	}
+	profiles := make(map[string]int)
+
+	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
+
+	var line string
+	defer marshal.RecoverLine(&line)
+
+	for scanner.Scan() {
+		line = scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		splitted := strings.Split(line, "|||")
+		iter := marshal.NewIter(splitted)
+
+		switch strings.TrimSpace(iter()) {
+		case "USER":
+			user := model.OracleDatabaseUser{
+				Username:      strings.TrimSpace(iter()),
+				AccountStatus: strings.TrimSpace(iter()),
+				LockDate:      parseSecurityTime(securityTimeLayout, iter()),
+				ExpiryDate:    parseSecurityTime(securityTimeLayout, iter()),
+				Profile:       strings.TrimSpace(iter()),
+			}
+
+			// password_versions is a list separated by spaces, e.g. "10G 11G 12C "
+			user.PasswordVersions = strings.Fields(iter())
+			for _, version := range user.PasswordVersions {
+				if version == "10G" {
+					user.Has10GPassword = true
+				}
+			}
+
+			user.AuthenticationType = strings.TrimSpace(iter())
+			user.LastLogin = parseSecurityTime(securityTzTimeLayout, iter())
+
+			security.Users = append(security.Users, user)
+		case "PROFILE":
+			name := strings.TrimSpace(iter())
+			i, ok := profiles[name]
+			if !ok {
+				i = len(security.Profiles)
+				profiles[name] = i
+				security.Profiles = append(security.Profiles, model.OracleDatabaseProfile{Name: name})
+			}
+			profile := &security.Profiles[i]
+
+			resource := strings.TrimSpace(iter())
+			limit := strings.TrimSpace(iter())
+
+			switch resource {
+			case "FAILED_LOGIN_ATTEMPTS":
+				profile.FailedLoginAttempts = limit
+			case "PASSWORD_LIFE_TIME":
+				profile.PasswordLifeTime = limit
+			case "PASSWORD_GRACE_TIME":
+				profile.PasswordGraceTime = limit
+			case "PASSWORD_LOCK_TIME":
+				profile.PasswordLockTime = limit
+			case "PASSWORD_REUSE_MAX":
+				profile.PasswordReuseMax = limit
+			case "PASSWORD_REUSE_TIME":
+				profile.PasswordReuseTime = limit
+			case "PASSWORD_VERIFY_FUNCTION":
+				profile.PasswordVerifyFunction = limit
+			}
+		case "DEFPWD":
+			security.DefaultPasswordUsers = append(security.DefaultPasswordUsers, strings.TrimSpace(iter()))
+		}
+	}
+
+	return security
+}
+
+// parseSecurityTime returns nil if s is empty, times without offset are in the local timezone
+func parseSecurityTime(layout, s string) *time.Time {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+
+	t, err := time.ParseInLocation(layout, s, time.Local)
+	if err != nil {
+		panic(err)
+	}
+
+	return &t
+}
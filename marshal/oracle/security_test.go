@@ -0,0 +1,112 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package oracle
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+// utcUsers returns users with their times in UTC, they can be compared whatever the location they were parsed in
+func utcUsers(users []model.OracleDatabaseUser) []model.OracleDatabaseUser {
+	utc := func(t *time.Time) *time.Time {
+		if t == nil {
+			return nil
+		}
+		u := t.UTC()
+		return &u
+	}
+
+	result := make([]model.OracleDatabaseUser, len(users))
+	for i, user := range users {
+		user.LockDate = utc(user.LockDate)
+		user.ExpiryDate = utc(user.ExpiryDate)
+		user.LastLogin = utc(user.LastLogin)
+		result[i] = user
+	}
+
+	return result
+}
+
+func TestSecurity(t *testing.T) {
+	cmdOutput := `
+USER|||APP_OWNER                     |||OPEN                            |||                   |||2024-11-02 10:15:00|||APP_PROFILE                   |||11G 12C          |||PASSWORD|||2024-05-06 09:12:44 +02:00
+USER|||OPS$ORACLE                    |||OPEN                            |||                   |||                   |||DEFAULT                       |||                 |||EXTERNAL|||
+USER|||SCOTT                         |||EXPIRED & LOCKED                |||2023-01-15 18:00:00|||2023-01-15 18:00:00|||DEFAULT                       |||10G 11G 12C      |||PASSWORD|||
+
+PROFILE|||APP_PROFILE                   |||FAILED_LOGIN_ATTEMPTS           |||5
+PROFILE|||APP_PROFILE                   |||PASSWORD_LIFE_TIME              |||90
+PROFILE|||APP_PROFILE                   |||PASSWORD_VERIFY_FUNCTION        |||ORA12C_STRONG_VERIFY_FUNCTION
+PROFILE|||DEFAULT                       |||FAILED_LOGIN_ATTEMPTS           |||10
+PROFILE|||DEFAULT                       |||PASSWORD_GRACE_TIME             |||7
+PROFILE|||DEFAULT                       |||PASSWORD_LIFE_TIME              |||180
+PROFILE|||DEFAULT                       |||PASSWORD_LOCK_TIME              |||1
+PROFILE|||DEFAULT                       |||PASSWORD_REUSE_MAX              |||UNLIMITED
+PROFILE|||DEFAULT                       |||PASSWORD_REUSE_TIME             |||UNLIMITED
+PROFILE|||DEFAULT                       |||PASSWORD_ROLLOVER_TIME          |||0
+PROFILE|||DEFAULT                       |||PASSWORD_VERIFY_FUNCTION        |||NULL
+PROFILE|||APP_PROFILE                   |||PASSWORD_REUSE_MAX              |||DEFAULT
+
+DEFPWD|||SCOTT
+`
+
+	wantUsers := []model.OracleDatabaseUser{
+		{Username: "APP_OWNER", AccountStatus: "OPEN", ExpiryDate: localTime(2024, 11, 2, 10, 15, 0), Profile: "APP_PROFILE",
+			PasswordVersions: []string{"11G", "12C"}, AuthenticationType: "PASSWORD",
+			LastLogin: func() *time.Time {
+				t := time.Date(2024, 5, 6, 7, 12, 44, 0, time.UTC)
+				return &t
+			}()},
+		{Username: "OPS$ORACLE", AccountStatus: "OPEN", Profile: "DEFAULT", PasswordVersions: []string{},
+			AuthenticationType: "EXTERNAL"},
+		{Username: "SCOTT", AccountStatus: "EXPIRED & LOCKED", LockDate: localTime(2023, 1, 15, 18, 0, 0),
+			ExpiryDate: localTime(2023, 1, 15, 18, 0, 0), Profile: "DEFAULT", PasswordVersions: []string{"10G", "11G", "12C"},
+			Has10GPassword: true, AuthenticationType: "PASSWORD"},
+	}
+	wantProfiles := []model.OracleDatabaseProfile{
+		{Name: "APP_PROFILE", FailedLoginAttempts: "5", PasswordLifeTime: "90", PasswordReuseMax: "DEFAULT",
+			PasswordVerifyFunction: "ORA12C_STRONG_VERIFY_FUNCTION"},
+		{Name: "DEFAULT", FailedLoginAttempts: "10", PasswordLifeTime: "180", PasswordGraceTime: "7", PasswordLockTime: "1",
+			PasswordReuseMax: "UNLIMITED", PasswordReuseTime: "UNLIMITED", PasswordVerifyFunction: "NULL"},
+	}
+
+	got := Security([]byte(cmdOutput))
+
+	if !reflect.DeepEqual(utcUsers(got.Users), utcUsers(wantUsers)) {
+		t.Errorf("got users %+v, want %+v", got.Users, wantUsers)
+	}
+	if !reflect.DeepEqual(got.Profiles, wantProfiles) {
+		t.Errorf("got profiles %+v, want %+v", got.Profiles, wantProfiles)
+	}
+	if !reflect.DeepEqual(got.DefaultPasswordUsers, []string{"SCOTT"}) {
+		t.Errorf("got default password users %v, want [SCOTT]", got.DefaultPasswordUsers)
+	}
+}
+
+func TestSecurityEmpty(t *testing.T) {
+	want := model.OracleDatabaseSecurity{
+		Users:                []model.OracleDatabaseUser{},
+		Profiles:             []model.OracleDatabaseProfile{},
+		DefaultPasswordUsers: []string{},
+	}
+
+	if got := Security([]byte("")); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
@@ -28,8 +28,11 @@ func SegmentAdvisor(cmdOutput []byte) []model.OracleDatabaseSegmentAdvisor {
 	segmentadvisors := []model.OracleDatabaseSegmentAdvisor{}
 	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
 
+	var line string
+	defer marshal.RecoverLine(&line)
+
 	for scanner.Scan() {
-		line := scanner.Text()
+		line = scanner.Text()
 
 		splitted := strings.Split(line, "|||")
 
@@ -0,0 +1,113 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package oracle
+
+import (
+	"bufio"
+	"strings"
+
+	"github.com/ercole-io/ercole-agent-rhel5/agentmodel"
+	"github.com/ercole-io/ercole-agent-rhel5/marshal"
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+// Services returns information about database services extracted
+// from the service fetchers command output.
+func Services(cmdOutput []byte) []model.OracleDatabaseService {
+	services := []model.OracleDatabaseService{}
+	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
+
+	var line string
+	defer marshal.RecoverLine(&line)
+
+	for scanner.Scan() {
+		line = scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		splitted := strings.Split(line, "|||")
+
+		services = append(services, model.OracleDatabaseService{
+			Name:               strings.TrimSpace(splitted[0]),
+			FailoverType:       strings.TrimSpace(splitted[1]),
+			PreferredInstances: []string{},
+			AvailableInstances: []string{},
+			Running:            marshal.TrimParseBool(splitted[2]),
+		})
+	}
+	return services
+}
+
+// ClusterwareServices returns the configuration of the services extracted
+// from the srvctl_service fetcher command output, in the "Key: value" format of srvctl 11.2 and later.
+func ClusterwareServices(cmdOutput []byte) []agentmodel.ClusterwareService {
+	services := []agentmodel.ClusterwareService{}
+	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
+
+	var line string
+	defer marshal.RecoverLine(&line)
+
+	var service *agentmodel.ClusterwareService
+
+	for scanner.Scan() {
+		line = scanner.Text()
+
+		splitted := strings.SplitN(line, ":", 2)
+		if len(splitted) != 2 {
+			continue
+		}
+
+		key := strings.TrimSpace(splitted[0])
+		value := strings.TrimSpace(splitted[1])
+
+		if key == "Service name" {
+			services = append(services, agentmodel.ClusterwareService{
+				Name:               value,
+				PreferredInstances: []string{},
+				AvailableInstances: []string{},
+			})
+			service = &services[len(services)-1]
+			continue
+		}
+
+		if service == nil {
+			continue
+		}
+
+		switch key {
+		case "Pluggable database name":
+			service.PDB = value
+		case "Failover type":
+			service.FailoverType = value
+		case "Preferred instances":
+			service.PreferredInstances = splitInstances(value)
+		case "Available instances":
+			service.AvailableInstances = splitInstances(value)
+		}
+	}
+	return services
+}
+
+func splitInstances(value string) []string {
+	instances := []string{}
+	for _, instance := range strings.Split(value, ",") {
+		if instance = strings.TrimSpace(instance); instance != "" {
+			instances = append(instances, instance)
+		}
+	}
+	return instances
+}
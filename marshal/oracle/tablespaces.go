@@ -29,9 +29,12 @@ func Tablespaces(cmdOutput []byte) []model.OracleDatabaseTablespace {
 	tablespaces := []model.OracleDatabaseTablespace{}
 	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
 
+	var line string
+	defer marshal.RecoverLine(&line)
+
 	for scanner.Scan() {
 		tablespace := new(model.OracleDatabaseTablespace)
-		line := scanner.Text()
+		line = scanner.Text()
 		splitted := strings.Split(line, "|||")
 		if len(splitted) == 9 {
 			tablespace.Name = strings.TrimSpace(splitted[3])
@@ -29,8 +29,11 @@ func VmwareVMs(cmdOutput []byte) map[string][]model.VMInfo {
 	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
 	vms := map[string][]model.VMInfo{}
 
+	var line string
+	defer RecoverLine(&line)
+
 	for scanner.Scan() {
-		line := scanner.Text()
+		line = scanner.Text()
 		splitted := strings.Split(line, ",")
 		if len(splitted) == 3 && splitted[0] == "Cluster" && splitted[1] == "Name" && splitted[2] == "guestHostname" {
 			continue
@@ -60,8 +63,11 @@ func OvmVMs(cmdOutput []byte) map[string][]model.VMInfo {
 	scanner := bufio.NewScanner(strings.NewReader(string(cmdOutput)))
 	vms := map[string][]model.VMInfo{}
 
+	var line string
+	defer RecoverLine(&line)
+
 	for scanner.Scan() {
-		line := scanner.Text()
+		line = scanner.Text()
 		splitted := strings.Split(line, ",")
 		if len(splitted) < 5 {
 			continue
@@ -0,0 +1,41 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package model
+
+// CollectionError holds informations about a section of the hostdata that couldn't be collected
+type CollectionError struct {
+	Section   string                 `json:"section" bson:"section"`
+	Database  string                 `json:"database" bson:"database"`
+	Fetcher   string                 `json:"fetcher" bson:"fetcher"`
+	Class     string                 `json:"class" bson:"class"`
+	Message   string                 `json:"message" bson:"message"`
+	Duration  float64                `json:"duration" bson:"duration"`
+	OtherInfo map[string]interface{} `json:"-" bson:"-"`
+}
+
+// CollectionError classes
+const (
+	CollectionErrorClassTimeout   = "TIMEOUT"
+	CollectionErrorClassExit      = "EXIT_STATUS"
+	CollectionErrorClassSignal    = "SIGNAL"
+	CollectionErrorClassCancel    = "CANCELED"
+	CollectionErrorClassExec      = "EXEC"
+	CollectionErrorClassOracle    = "ORACLE_ERROR"
+	CollectionErrorClassIntegrity = "INTEGRITY"
+	CollectionErrorClassMarshal   = "MARSHAL"
+	CollectionErrorClassPanic     = "PANIC"
+	CollectionErrorClassGeneric   = "ERROR"
+)
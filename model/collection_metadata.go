@@ -0,0 +1,40 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package model
+
+// CollectionMetadata holds informations about how the hostdata was collected
+type CollectionMetadata struct {
+	Duration  float64                    `json:"duration" bson:"duration"`
+	Sections  []CollectionSectionTiming  `json:"sections" bson:"sections"`
+	Databases []CollectionDatabaseTiming `json:"databases" bson:"databases"`
+	OtherInfo map[string]interface{}     `json:"-" bson:"-"`
+}
+
+// CollectionSectionTiming holds the time spent by the fetchers of a section, in seconds
+type CollectionSectionTiming struct {
+	Section   string                 `json:"section" bson:"section"`
+	Database  string                 `json:"database" bson:"database"`
+	Calls     int                    `json:"calls" bson:"calls"`
+	Duration  float64                `json:"duration" bson:"duration"`
+	OtherInfo map[string]interface{} `json:"-" bson:"-"`
+}
+
+// CollectionDatabaseTiming holds the time spent collecting a database, in seconds
+type CollectionDatabaseTiming struct {
+	Database  string                 `json:"database" bson:"database"`
+	Duration  float64                `json:"duration" bson:"duration"`
+	OtherInfo map[string]interface{} `json:"-" bson:"-"`
+}
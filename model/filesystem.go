@@ -15,7 +15,8 @@
 
 package model
 
-// Filesystem holds information about mounted filesystem and used space
+// Filesystem holds information about mounted filesystem and used space.
+// Sizes are in KiB, like df -P.
 type Filesystem struct {
 	Filesystem     string                 `json:"filesystem" bson:"filesystem"`
 	Type           string                 `json:"type" bson:"type"`
@@ -23,5 +24,10 @@ type Filesystem struct {
 	UsedSpace      int64                  `json:"usedSpace" bson:"usedSpace"`
 	AvailableSpace int64                  `json:"availableSpace" bson:"availableSpace"`
 	MountedOn      string                 `json:"mountedOn" bson:"mountedOn"`
+	MountOptions   string                 `json:"mountOptions" bson:"mountOptions"`
+	ReadOnly       bool                   `json:"readOnly" bson:"readOnly"`
+	Inodes         int64                  `json:"inodes" bson:"inodes"`
+	UsedInodes     int64                  `json:"usedInodes" bson:"usedInodes"`
+	FreeInodes     int64                  `json:"freeInodes" bson:"freeInodes"`
 	OtherInfo      map[string]interface{} `json:"-" bson:"-"`
 }
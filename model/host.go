@@ -21,6 +21,7 @@ const (
 	HardwareAbstractionTechnologyOvm      string = "OVM"
 	HardwareAbstractionTechnologyVmware   string = "VMWARE"
 	HardwareAbstractionTechnologyHyperv   string = "HYPERV"
+	HardwareAbstractionTechnologyKvm      string = "KVM"
 	HardwareAbstractionTechnologyVmother  string = "VMOTHER"
 	HardwareAbstractionTechnologyXen      string = "XEN"
 	HardwareAbstractionTechnologyHpvirt   string = "HPVIRT"
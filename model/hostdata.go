@@ -32,5 +32,7 @@ type HostData struct {
 	Features                Features                `json:"features"`
 	Filesystems             []Filesystem            `json:"filesystems"`
 	Clusters                []ClusterInfo           `json:"clusters"`
+	CollectionErrors        []CollectionError       `json:"collectionErrors"`
+	CollectionMetadata      *CollectionMetadata     `json:"collectionMetadata,omitempty"`
 	OtherInfo               map[string]interface{}  `json:"-"`
 }
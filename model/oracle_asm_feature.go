@@ -0,0 +1,49 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package model
+
+// OracleASMFeature holds information about the ASM instance of the host
+type OracleASMFeature struct {
+	InstanceName string                 `json:"instanceName" bson:"instanceName"`
+	DiskGroups   []OracleASMDiskGroup   `json:"diskGroups" bson:"diskGroups"`
+	OtherInfo    map[string]interface{} `json:"-" bson:"-"`
+}
+
+// OracleASMDiskGroup holds information about an ASM disk group. Sizes are in MB
+type OracleASMDiskGroup struct {
+	Name               string                 `json:"name" bson:"name"`
+	Redundancy         string                 `json:"redundancy" bson:"redundancy"`
+	State              string                 `json:"state" bson:"state"`
+	Total              int64                  `json:"total" bson:"total"`
+	Free               int64                  `json:"free" bson:"free"`
+	Usable             int64                  `json:"usable" bson:"usable"`
+	CompatibilityASM   string                 `json:"compatibilityASM" bson:"compatibilityASM"`
+	CompatibilityRDBMS string                 `json:"compatibilityRDBMS" bson:"compatibilityRDBMS"`
+	Disks              []OracleASMDisk        `json:"disks" bson:"disks"`
+	OtherInfo          map[string]interface{} `json:"-" bson:"-"`
+}
+
+// OracleASMDisk holds information about a disk of an ASM disk group. Size is in MB
+type OracleASMDisk struct {
+	Path         string                 `json:"path" bson:"path"`
+	Name         string                 `json:"name" bson:"name"`
+	FailGroup    string                 `json:"failGroup" bson:"failGroup"`
+	Size         int64                  `json:"size" bson:"size"`
+	MountStatus  string                 `json:"mountStatus" bson:"mountStatus"`
+	HeaderStatus string                 `json:"headerStatus" bson:"headerStatus"`
+	State        string                 `json:"state" bson:"state"`
+	OtherInfo    map[string]interface{} `json:"-" bson:"-"`
+}
@@ -29,6 +29,8 @@ type OracleDatabase struct {
 	DbID              uint                              `json:"dbID" bson:"dbID"`
 	Role              string                            `json:"role" bson:"role"`
 	IsCDB             bool                              `json:"isCDB" bson:"isCDB"`
+	IsRAC             bool                              `json:"isRAC" bson:"isRAC"`
+	Instances         []OracleDatabaseInstance          `json:"instances" bson:"instances"`
 	Version           string                            `json:"version" bson:"version"`
 	Platform          string                            `json:"platform" bson:"platform"`
 	Archivelog        bool                              `json:"archivelog" bson:"archivelog"`
@@ -49,6 +51,7 @@ type OracleDatabase struct {
 	Work              *float64                          `json:"work" bson:"work"`
 	ASM               bool                              `json:"asm" bson:"asm"`
 	Dataguard         bool                              `json:"dataguard" bson:"dataguard"`
+	DataguardStatus   *OracleDatabaseDataguardStatus    `json:"dataguardStatus" bson:"dataguardStatus"`
 	Patches           []OracleDatabasePatch             `json:"patches" bson:"patches"`
 	Tablespaces       []OracleDatabaseTablespace        `json:"tablespaces" bson:"tablespaces"`
 	Schemas           []OracleDatabaseSchema            `json:"schemas" bson:"schemas"`
@@ -57,11 +60,16 @@ type OracleDatabase struct {
 	SegmentAdvisors   []OracleDatabaseSegmentAdvisor    `json:"segmentAdvisors" bson:"segmentAdvisors"`
 	PSUs              []OracleDatabasePSU               `json:"psus" bson:"psus"`
 	Backups           []OracleDatabaseBackup            `json:"backups" bson:"backups"`
+	BackupJobs        []OracleDatabaseBackupJob         `json:"backupJobs" bson:"backupJobs"`
+	LastBackups       OracleDatabaseLastBackups         `json:"lastBackups" bson:"lastBackups"`
 	FeatureUsageStats []OracleDatabaseFeatureUsageStat  `json:"featureUsageStats" bson:"featureUsageStats"`
 	PDBs              []OracleDatabasePluggableDatabase `json:"pdbs" bson:"pdbs"`
 	Services          []OracleDatabaseService           `json:"services" bson:"services"`
 	GrantDba          []OracleGrantDba                  `json:"grantDba" bson:"grantDba"`
+	Security          *OracleDatabaseSecurity           `json:"security" bson:"security"`
 	Partitionings     []OracleDatabasePartitioning      `json:"partitionings" bson:"partitionings"`
+	Parameters        []OracleDatabaseParameter         `json:"parameters" bson:"parameters"`
+	SkippedSections   []string                          `json:"skippedSections" bson:"skippedSections"`
 	OtherInfo         map[string]interface{}            `json:"-" bson:"-"`
 }
 
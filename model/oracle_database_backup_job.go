@@ -0,0 +1,39 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package model
+
+import "time"
+
+// OracleDatabaseBackupJob holds an RMAN job of the backup history
+type OracleDatabaseBackupJob struct {
+	StartTime   time.Time              `json:"startTime" bson:"startTime"`
+	EndTime     *time.Time             `json:"endTime" bson:"endTime"`
+	Status      string                 `json:"status" bson:"status"`
+	InputType   string                 `json:"inputType" bson:"inputType"`
+	InputBytes  int64                  `json:"inputBytes" bson:"inputBytes"`
+	OutputBytes int64                  `json:"outputBytes" bson:"outputBytes"`
+	DeviceType  string                 `json:"deviceType" bson:"deviceType"`
+	OtherInfo   map[string]interface{} `json:"-" bson:"-"`
+}
+
+// OracleDatabaseLastBackups holds the end of the most recent successful RMAN backups, nil if there's none.
+// Incremental includes level 0 backups
+type OracleDatabaseLastBackups struct {
+	Full        *time.Time             `json:"full" bson:"full"`
+	Incremental *time.Time             `json:"incremental" bson:"incremental"`
+	Archivelog  *time.Time             `json:"archivelog" bson:"archivelog"`
+	OtherInfo   map[string]interface{} `json:"-" bson:"-"`
+}
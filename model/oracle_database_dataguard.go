@@ -0,0 +1,51 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package model
+
+// OracleDatabaseDataguardStatus holds the Data Guard configuration and state of a primary or standby database.
+// Lags are in seconds, nil if they aren't available
+type OracleDatabaseDataguardStatus struct {
+	Role                 string                                   `json:"role" bson:"role"`
+	ProtectionMode       string                                   `json:"protectionMode" bson:"protectionMode"`
+	ProtectionLevel      string                                   `json:"protectionLevel" bson:"protectionLevel"`
+	SwitchoverStatus     string                                   `json:"switchoverStatus" bson:"switchoverStatus"`
+	BrokerEnabled        bool                                     `json:"brokerEnabled" bson:"brokerEnabled"`
+	FastStartFailover    string                                   `json:"fastStartFailover" bson:"fastStartFailover"`
+	Destinations         []OracleDatabaseDataguardDestination     `json:"destinations" bson:"destinations"`
+	TransportLag         *int64                                   `json:"transportLag" bson:"transportLag"`
+	ApplyLag             *int64                                   `json:"applyLag" bson:"applyLag"`
+	LastAppliedSequences []OracleDatabaseDataguardAppliedSequence `json:"lastAppliedSequences" bson:"lastAppliedSequences"`
+	OtherInfo            map[string]interface{}                   `json:"-" bson:"-"`
+}
+
+// OracleDatabaseDataguardDestination holds a log_archive_dest_n targeting a standby
+type OracleDatabaseDataguardDestination struct {
+	ID           int                    `json:"id" bson:"id"`
+	Destination  string                 `json:"destination" bson:"destination"`
+	DBUniqueName string                 `json:"dbUniqueName" bson:"dbUniqueName"`
+	Status       string                 `json:"status" bson:"status"`
+	TransmitMode string                 `json:"transmitMode" bson:"transmitMode"`
+	Affirm       bool                   `json:"affirm" bson:"affirm"`
+	Error        string                 `json:"error" bson:"error"`
+	OtherInfo    map[string]interface{} `json:"-" bson:"-"`
+}
+
+// OracleDatabaseDataguardAppliedSequence holds the last applied log sequence of a redo thread
+type OracleDatabaseDataguardAppliedSequence struct {
+	Thread    int                    `json:"thread" bson:"thread"`
+	Sequence  int64                  `json:"sequence" bson:"sequence"`
+	OtherInfo map[string]interface{} `json:"-" bson:"-"`
+}
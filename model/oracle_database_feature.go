@@ -16,7 +16,16 @@
 package model
 
 type OracleDatabaseFeature struct {
-	Databases                []OracleDatabase       `json:"databases" bson:"databases"`
-	UnlistedRunningDatabases []string               `json:"unlistedRunningDatabases" bson:"unlistedRunningDatabases"`
-	OtherInfo                map[string]interface{} `json:"-" bson:"-"`
+	Databases                []OracleDatabase                `json:"databases" bson:"databases"`
+	UnlistedRunningDatabases []string                        `json:"unlistedRunningDatabases" bson:"unlistedRunningDatabases"`
+	SkippedDatabases         []OracleDatabaseSkippedDatabase `json:"skippedDatabases" bson:"skippedDatabases"`
+	OtherInfo                map[string]interface{}          `json:"-" bson:"-"`
+}
+
+// OracleDatabaseSkippedDatabase holds an oratab entry not collected because of the configured filters
+type OracleDatabaseSkippedDatabase struct {
+	Name       string                 `json:"name" bson:"name"`
+	OracleHome string                 `json:"oracleHome" bson:"oracleHome"`
+	Reason     string                 `json:"reason" bson:"reason"`
+	OtherInfo  map[string]interface{} `json:"-" bson:"-"`
 }
@@ -0,0 +1,26 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package model
+
+// OracleDatabaseInstance holds information about a running instance of an Oracle database
+type OracleDatabaseInstance struct {
+	Number    int                    `json:"number" bson:"number"`
+	Name      string                 `json:"name" bson:"name"`
+	Host      string                 `json:"host" bson:"host"`
+	Status    string                 `json:"status" bson:"status"`
+	Local     bool                   `json:"local" bson:"local"`
+	OtherInfo map[string]interface{} `json:"-" bson:"-"`
+}
@@ -0,0 +1,29 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package model
+
+// OracleDatabaseParameter holds an initialization parameter of an instance or of a pdb.
+// SPFileValue is the display value in the spfile, nil if the parameter isn't specified there
+type OracleDatabaseParameter struct {
+	Name          string                 `json:"name" bson:"name"`
+	Value         string                 `json:"value" bson:"value"`
+	IsDefault     bool                   `json:"isDefault" bson:"isDefault"`
+	IsModified    string                 `json:"isModified" bson:"isModified"`
+	IsBasic       bool                   `json:"isBasic" bson:"isBasic"`
+	SPFileValue   *string                `json:"spfileValue" bson:"spfileValue"`
+	SPFileDiffers bool                   `json:"spfileDiffers" bson:"spfileDiffers"`
+	OtherInfo     map[string]interface{} `json:"-" bson:"-"`
+}
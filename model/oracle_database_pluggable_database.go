@@ -22,5 +22,6 @@ type OracleDatabasePluggableDatabase struct {
 	Tablespaces []OracleDatabaseTablespace `json:"tablespaces" bson:"tablespaces"`
 	Schemas     []OracleDatabaseSchema     `json:"schemas" bson:"schemas"`
 	Services    []OracleDatabaseService    `json:"services" bson:"services"`
+	Parameters  []OracleDatabaseParameter  `json:"parameters" bson:"parameters"`
 	OtherInfo   map[string]interface{}     `json:"-" bson:"-"`
 }
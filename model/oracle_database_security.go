@@ -0,0 +1,55 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package model
+
+import "time"
+
+// OracleDatabaseSecurity holds the accounts and the password profiles of a database
+type OracleDatabaseSecurity struct {
+	Users                []OracleDatabaseUser    `json:"users" bson:"users"`
+	Profiles             []OracleDatabaseProfile `json:"profiles" bson:"profiles"`
+	DefaultPasswordUsers []string                `json:"defaultPasswordUsers" bson:"defaultPasswordUsers"`
+	OtherInfo            map[string]interface{}  `json:"-" bson:"-"`
+}
+
+// OracleDatabaseUser holds an account of the database. PasswordVersions is empty before 11g,
+// LastLogin is always nil before 12c
+type OracleDatabaseUser struct {
+	Username           string                 `json:"username" bson:"username"`
+	AccountStatus      string                 `json:"accountStatus" bson:"accountStatus"`
+	LockDate           *time.Time             `json:"lockDate" bson:"lockDate"`
+	ExpiryDate         *time.Time             `json:"expiryDate" bson:"expiryDate"`
+	Profile            string                 `json:"profile" bson:"profile"`
+	PasswordVersions   []string               `json:"passwordVersions" bson:"passwordVersions"`
+	Has10GPassword     bool                   `json:"has10GPassword" bson:"has10GPassword"`
+	AuthenticationType string                 `json:"authenticationType" bson:"authenticationType"`
+	LastLogin          *time.Time             `json:"lastLogin" bson:"lastLogin"`
+	OtherInfo          map[string]interface{} `json:"-" bson:"-"`
+}
+
+// OracleDatabaseProfile holds the password limits of a profile, as written in dba_profiles:
+// a value, UNLIMITED, DEFAULT or NULL for the verify function
+type OracleDatabaseProfile struct {
+	Name                   string                 `json:"name" bson:"name"`
+	FailedLoginAttempts    string                 `json:"failedLoginAttempts" bson:"failedLoginAttempts"`
+	PasswordLifeTime       string                 `json:"passwordLifeTime" bson:"passwordLifeTime"`
+	PasswordGraceTime      string                 `json:"passwordGraceTime" bson:"passwordGraceTime"`
+	PasswordLockTime       string                 `json:"passwordLockTime" bson:"passwordLockTime"`
+	PasswordReuseMax       string                 `json:"passwordReuseMax" bson:"passwordReuseMax"`
+	PasswordReuseTime      string                 `json:"passwordReuseTime" bson:"passwordReuseTime"`
+	PasswordVerifyFunction string                 `json:"passwordVerifyFunction" bson:"passwordVerifyFunction"`
+	OtherInfo              map[string]interface{} `json:"-" bson:"-"`
+}
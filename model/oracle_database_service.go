@@ -17,6 +17,10 @@ package model
 
 // OracleDatabaseService holds information about an Oracle database service
 type OracleDatabaseService struct {
-	Name      string                 `json:"name" bson:"name"`
-	OtherInfo map[string]interface{} `json:"-" bson:"-"`
+	Name               string                 `json:"name" bson:"name"`
+	FailoverType       string                 `json:"failoverType" bson:"failoverType"`
+	PreferredInstances []string               `json:"preferredInstances" bson:"preferredInstances"`
+	AvailableInstances []string               `json:"availableInstances" bson:"availableInstances"`
+	Running            bool                   `json:"running" bson:"running"`
+	OtherInfo          map[string]interface{} `json:"-" bson:"-"`
 }
@@ -18,5 +18,6 @@ package model
 type OracleFeature struct {
 	Database  *OracleDatabaseFeature `json:"database,omitempty" bson:"database,omitempty"`
 	Exadata   *OracleExadataFeature  `json:"exadata,omitempty" bson:"exadata,omitempty"`
+	ASM       *OracleASMFeature      `json:"asm,omitempty" bson:"asm,omitempty"`
 	OtherInfo map[string]interface{} `json:"-" bson:"-"`
 }
@@ -35,7 +35,9 @@ exit 0
 %setup -q -n %{name}-%{version}
 
 rm -rf %{buildroot}
-make DESTDIR=%{buildroot}/opt/ercole-agent install
+# The manifest of the fetchers is signed with the key given by rpmbuild --define "manifest_key /path/to/key.pem",
+# the build fails without it
+make DESTDIR=%{buildroot}/opt/ercole-agent MANIFEST_KEY=%{?manifest_key} install
 install -d %{buildroot}/etc/systemd/system
 install -d %{buildroot}/opt/ercole-agent/run
 install -d %{buildroot}%{_unitdir} 
@@ -59,11 +61,15 @@ install -m 0644 package/rhel7/60-ercole-agent.preset %{buildroot}%{_presetdir}/6
 %dir /opt/ercole-agent/fetch
 %dir /opt/ercole-agent/sql
 %config(noreplace) /opt/ercole-agent/config.json
+/opt/ercole-agent/MANIFEST*
 /opt/ercole-agent/ercole-agent
 /opt/ercole-agent/ercole-setup
 /opt/ercole-agent/fetch/linux/addm.sh
+/opt/ercole-agent/fetch/linux/asm.sh
 /opt/ercole-agent/fetch/linux/backup.sh
+/opt/ercole-agent/fetch/linux/backup_history.sh
 /opt/ercole-agent/fetch/linux/checkpdb.sh
+/opt/ercole-agent/fetch/linux/dataguard.sh
 /opt/ercole-agent/fetch/linux/cluster_membership_status.sh
 /opt/ercole-agent/fetch/linux/db.sh
 /opt/ercole-agent/fetch/linux/dbmounted.sh
@@ -72,43 +78,63 @@ install -m 0644 package/rhel7/60-ercole-agent.preset %{buildroot}%{_presetdir}/6
 /opt/ercole-agent/fetch/linux/filesystem.sh
 /opt/ercole-agent/fetch/linux/grant_dba.sh
 /opt/ercole-agent/fetch/linux/host.sh
+/opt/ercole-agent/fetch/linux/instances.sh
 /opt/ercole-agent/fetch/linux/license.sh
 /opt/ercole-agent/fetch/linux/listpdb.sh
 /opt/ercole-agent/fetch/linux/opt.sh
+/opt/ercole-agent/fetch/linux/parameters.sh
+/opt/ercole-agent/fetch/linux/parameters_pdb.sh
 /opt/ercole-agent/fetch/linux/oratab.sh
 /opt/ercole-agent/fetch/linux/ovm.sh
 /opt/ercole-agent/fetch/linux/patch.sh
 /opt/ercole-agent/fetch/linux/psu.sh
 /opt/ercole-agent/fetch/linux/schema.sh
 /opt/ercole-agent/fetch/linux/schema_pdb.sh
+/opt/ercole-agent/fetch/linux/security.sh
+/opt/ercole-agent/fetch/linux/service.sh
+/opt/ercole-agent/fetch/linux/service_pdb.sh
 /opt/ercole-agent/fetch/linux/segmentadvisor.sh
+/opt/ercole-agent/fetch/linux/srvctl_service.sh
 /opt/ercole-agent/fetch/linux/stats.sh
 /opt/ercole-agent/fetch/linux/tablespace.sh
 /opt/ercole-agent/fetch/linux/tablespace_pdb.sh
 /opt/ercole-agent/fetch/linux/oracle_running_databases.sh
+/opt/ercole-agent/fetch/linux/oracle_running_homes.sh
+/opt/ercole-agent/fetch/linux/oracle_clusterware_databases.sh
 /opt/ercole-agent/fetch/linux/partitioning.sh
 /opt/ercole-agent/fetch/linux/vmware.ps1
 /opt/ercole-agent/fetch/linux/exadata/info.sh
 /opt/ercole-agent/fetch/linux/exadata/storage-status.sh
 /opt/ercole-agent/sql/addm.sql
+/opt/ercole-agent/sql/asm.sql
+/opt/ercole-agent/sql/backup_history.sql
 /opt/ercole-agent/sql/backup_schedule.sql
 /opt/ercole-agent/sql/checkpdb.sql
+/opt/ercole-agent/sql/dataguard.sql
 /opt/ercole-agent/sql/db.sql
 /opt/ercole-agent/sql/dbmounted.sql
 /opt/ercole-agent/sql/dbone.sql
 /opt/ercole-agent/sql/dbstatus.sql
 /opt/ercole-agent/sql/edition.sql
 /opt/ercole-agent/sql/grant_dba.sql
+/opt/ercole-agent/sql/instances.sql
 /opt/ercole-agent/sql/license-10.sql
 /opt/ercole-agent/sql/license.sql
 /opt/ercole-agent/sql/listpdb.sql
 /opt/ercole-agent/sql/opt.sql
+/opt/ercole-agent/sql/parameters.sql
+/opt/ercole-agent/sql/parameters_pdb.sql
 /opt/ercole-agent/sql/patch-12.sql
 /opt/ercole-agent/sql/patch.sql
 /opt/ercole-agent/sql/psu-1.sql
 /opt/ercole-agent/sql/psu-2.sql
 /opt/ercole-agent/sql/schema.sql
 /opt/ercole-agent/sql/schema_pdb.sql
+/opt/ercole-agent/sql/security-10.sql
+/opt/ercole-agent/sql/security-11.sql
+/opt/ercole-agent/sql/security.sql
+/opt/ercole-agent/sql/service.sql
+/opt/ercole-agent/sql/service_pdb.sql
 /opt/ercole-agent/sql/segment_advisor.sql
 /opt/ercole-agent/sql/stats.sql
 /opt/ercole-agent/sql/ts.sql
@@ -19,9 +19,7 @@ package scheduler
 import (
 	"fmt"
 	"log"
-	"os"
-	"os/signal"
-	"syscall"
+	"sync"
 	"time"
 
 	"github.com/ercole-io/ercole-agent-rhel5/scheduler/storage"
@@ -33,8 +31,16 @@ import (
 type Scheduler struct {
 	funcRegistry *task.FuncRegistry
 	stopChan     chan bool
+	stopped      chan struct{}
 	tasks        map[task.ID]*task.Task
 	taskStore    storeBridge
+	heartbeat    *heartbeat
+}
+
+// heartbeat holds the time of the last tick of the scheduler's timer
+type heartbeat struct {
+	lock     sync.Mutex
+	lastTick time.Time
 }
 
 // New will return a new instance of the Scheduler struct.
@@ -43,11 +49,13 @@ func New(store storage.TaskStore) Scheduler {
 	return Scheduler{
 		funcRegistry: funcRegistry,
 		stopChan:     make(chan bool),
+		stopped:      make(chan struct{}),
 		tasks:        make(map[task.ID]*task.Task),
 		taskStore: storeBridge{
 			store:        store,
 			funcRegistry: funcRegistry,
 		},
+		heartbeat: &heartbeat{},
 	}
 }
 
@@ -91,9 +99,6 @@ func (scheduler *Scheduler) RunEvery(duration time.Duration, function task.Funct
 // Start will run the scheduler's timer and will trigger the execution
 // of tasks depending on their schedule.
 func (scheduler *Scheduler) Start() error {
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-
 	// Populate tasks from storage
 	if err := scheduler.populateTasks(); err != nil {
 		return err
@@ -101,18 +106,21 @@ func (scheduler *Scheduler) Start() error {
 	if err := scheduler.persistRegisteredTasks(); err != nil {
 		return err
 	}
+	scheduler.tick()
 	scheduler.runPending()
 
 	go func() {
 		ticker := time.NewTicker(1 * time.Second)
+		defer ticker.Stop()
+		defer close(scheduler.stopped)
+
 		for {
 			select {
 			case <-ticker.C:
+				scheduler.tick()
 				scheduler.runPending()
-			case <-sigChan:
-				scheduler.stopChan <- true
 			case <-scheduler.stopChan:
-				close(scheduler.stopChan)
+				return
 			}
 		}
 	}()
@@ -120,14 +128,32 @@ func (scheduler *Scheduler) Start() error {
 	return nil
 }
 
-// Stop will put the scheduler to halt
+// Stop will put the scheduler to halt, no task is run after Stop returns.
+// Tasks already running aren't waited for.
 func (scheduler *Scheduler) Stop() {
 	scheduler.stopChan <- true
+	<-scheduler.stopped
 }
 
 // Wait is a convenience function for blocking until the scheduler is stopped.
 func (scheduler *Scheduler) Wait() {
-	<-scheduler.stopChan
+	<-scheduler.stopped
+}
+
+// LastTick returns the time of the last tick of the scheduler's timer,
+// zero if the scheduler wasn't started.
+func (scheduler *Scheduler) LastTick() time.Time {
+	scheduler.heartbeat.lock.Lock()
+	defer scheduler.heartbeat.lock.Unlock()
+
+	return scheduler.heartbeat.lastTick
+}
+
+func (scheduler *Scheduler) tick() {
+	scheduler.heartbeat.lock.Lock()
+	defer scheduler.heartbeat.lock.Unlock()
+
+	scheduler.heartbeat.lastTick = time.Now()
 }
 
 // Cancel is used to cancel the planned execution of a specific task using it's ID.
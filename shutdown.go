@@ -0,0 +1,91 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"os"
+	"time"
+)
+
+// Exit codes of the agent
+const (
+	exitCodeOK                 = 0
+	exitCodeError              = 1
+	exitCodeCollectionCanceled = 2
+	exitCodeShutdownTimeout    = 3
+)
+
+// beginCollection register a new collection, it returns false if the agent is shutting down
+func (p *program) beginCollection() bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.stopping {
+		return false
+	}
+
+	p.collections.Add(1)
+	return true
+}
+
+// endCollection return true if the collection was canceled by the shutdown
+func (p *program) endCollection() (canceled bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	select {
+	case <-p.cancel:
+		p.collectionCanceled = true
+		return true
+	default:
+		return false
+	}
+}
+
+// shutdown cancel the in-flight collection, killing its fetchers, and wait up
+// to gracePeriod for it to terminate. It return the exit code of the agent.
+func (p *program) shutdown(sig os.Signal, gracePeriod time.Duration) int {
+	p.log.Infof("Received signal [%v], shutting down", sig)
+
+	p.lock.Lock()
+	p.stopping = true
+	close(p.cancel)
+	p.lock.Unlock()
+
+	terminated := make(chan struct{})
+	go func() {
+		p.collections.Wait()
+		close(terminated)
+	}()
+
+	select {
+	case <-terminated:
+	case <-time.After(gracePeriod):
+		p.log.Errorf("Collection didn't terminate within the grace period of %v", gracePeriod)
+		return exitCodeShutdownTimeout
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.collectionCanceled {
+		p.log.Warn("Ercole agent stopped, the in-flight collection was canceled")
+		return exitCodeCollectionCanceled
+	}
+
+	p.log.Info("Ercole agent stopped")
+	return exitCodeOK
+}
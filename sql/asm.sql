@@ -0,0 +1,46 @@
+-- Copyright (c) 2023 Sorint.lab S.p.A.
+
+-- This program is free software: you can redistribute it and/or modify
+-- it under the terms of the GNU General Public License as published by
+-- the Free Software Foundation, either version 3 of the License, or
+-- (at your option) any later version.
+
+-- This program is distributed in the hope that it will be useful,
+-- but WITHOUT ANY WARRANTY; without even the implied warranty of
+-- MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+-- GNU General Public License for more details.
+
+-- You should have received a copy of the GNU General Public License
+-- along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+set lines 8000 pages 0 feedback off verify off timing off
+set colsep "|||"
+
+-- The _stat views don't run a discovery of the disks
+
+select 'DISKGROUP',
+       group_number,
+       name,
+       type,
+       state,
+       total_mb,
+       free_mb,
+       usable_file_mb,
+       compatibility,
+       database_compatibility
+from v$asm_diskgroup_stat
+order by name;
+
+select 'DISK',
+       group_number,
+       path,
+       name,
+       failgroup,
+       total_mb,
+       mount_status,
+       header_status,
+       state
+from v$asm_disk_stat
+where group_number > 0
+order by group_number, path;
+exit
@@ -0,0 +1,39 @@
+-- Copyright (c) 2023 Sorint.lab S.p.A.
+
+-- This program is free software: you can redistribute it and/or modify
+-- it under the terms of the GNU General Public License as published by
+-- the Free Software Foundation, either version 3 of the License, or
+-- (at your option) any later version.
+
+-- This program is distributed in the hope that it will be useful,
+-- but WITHOUT ANY WARRANTY; without even the implied warranty of
+-- MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+-- GNU General Public License for more details.
+
+-- You should have received a copy of the GNU General Public License
+-- along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+set lines 8000 pages 0 feedback off verify off timing off
+set colsep "|||"
+
+select 'JOB',
+       to_char(start_time, 'YYYY-MM-DD HH24:MI:SS'),
+       to_char(end_time, 'YYYY-MM-DD HH24:MI:SS'),
+       status,
+       input_type,
+       to_char(input_bytes),
+       to_char(output_bytes),
+       output_device_type
+from v$rman_backup_job_details
+where start_time > sysdate - &1
+order by start_time;
+
+-- The last successful backups of the whole controlfile history, not only of the last days
+select 'LAST',
+       input_type,
+       to_char(max(end_time), 'YYYY-MM-DD HH24:MI:SS')
+from v$rman_backup_job_details
+where status like 'COMPLETED%'
+and input_type in ('DB FULL', 'DB INCR', 'ARCHIVELOG')
+group by input_type;
+exit
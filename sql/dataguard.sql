@@ -0,0 +1,57 @@
+-- Copyright (c) 2023 Sorint.lab S.p.A.
+
+-- This program is free software: you can redistribute it and/or modify
+-- it under the terms of the GNU General Public License as published by
+-- the Free Software Foundation, either version 3 of the License, or
+-- (at your option) any later version.
+
+-- This program is distributed in the hope that it will be useful,
+-- but WITHOUT ANY WARRANTY; without even the implied warranty of
+-- MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+-- GNU General Public License for more details.
+
+-- You should have received a copy of the GNU General Public License
+-- along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+set lines 8000 pages 0 feedback off verify off timing off
+set colsep "|||"
+
+-- Only fixed views are queried, they are available also on mounted standbys
+
+select 'DATABASE',
+       database_role,
+       protection_mode,
+       protection_level,
+       switchover_status,
+       (select upper(value) from v$parameter where name = 'dg_broker_start'),
+       fs_failover_status
+from v$database;
+
+select 'DESTINATION',
+       dest_id,
+       destination,
+       db_unique_name,
+       status,
+       transmit_mode,
+       affirm,
+       error
+from v$archive_dest
+where target = 'STANDBY'
+and status <> 'INACTIVE'
+order by dest_id;
+
+select 'LAG',
+       name,
+       value
+from v$dataguard_stats
+where name in ('transport lag', 'apply lag');
+
+select 'APPLIED',
+       thread#,
+       max(sequence#)
+from v$archived_log
+where applied = 'YES'
+and resetlogs_change# = (select resetlogs_change# from v$database)
+group by thread#
+order by thread#;
+exit
@@ -35,7 +35,11 @@ select
 (select rtrim(to_char(value/1024/1024/1024, 'FM9G999G999D999', 'NLS_NUMERIC_CHARACTERS=''.,'''),',') from v$parameter where name='pga_aggregate_target') as Pga_Target,
 (select NVL(MIN(rtrim(to_char(value/1024/1024/1024, 'FM9G999G999D999', 'NLS_NUMERIC_CHARACTERS=''.,'''),',')),0) FROM v$parameter WHERE name='memory_target') AS Memory_Target,
 (select rtrim(to_char(value/1024/1024/1024, 'FM9G999G999D999', 'NLS_NUMERIC_CHARACTERS=''.,'''),',') from v$parameter where name='sga_max_size') as sga_max_size,
-'0','0','0','0','0','0','0',
+'0',
+nvl((select round(sum(bytes/1024/1024/1024)) from v$datafile), 0) +
+nvl((select round(sum(bytes/1024/1024/1024)) from v$tempfile), 0) +
+nvl((select round(sum(bytes/1024/1024/1024)) from v$log), 0) as Datafile_Size,
+'0','0','0','0','0',
 (select case when (select count(*) from v$datafile where name like '+%') > 0 then 'Y' else 'N' end as "ASM" from dual ),
 case when ( select count(*) from V$DATAGUARD_CONFIG) > 1 then 'Y' else 'N' end  as "Dataguard"
 from dual;
@@ -0,0 +1,27 @@
+-- Copyright (c) 2023 Sorint.lab S.p.A.
+
+-- This program is free software: you can redistribute it and/or modify
+-- it under the terms of the GNU General Public License as published by
+-- the Free Software Foundation, either version 3 of the License, or
+-- (at your option) any later version.
+
+-- This program is distributed in the hope that it will be useful,
+-- but WITHOUT ANY WARRANTY; without even the implied warranty of
+-- MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+-- GNU General Public License for more details.
+
+-- You should have received a copy of the GNU General Public License
+-- along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+set lines 8000 pages 0 feedback off verify off timing off
+set colsep "|||"
+
+select i.instance_number,
+       i.instance_name,
+       i.host_name,
+       i.status,
+       case when i.instance_number = (select instance_number from v$instance) then 'Y' else 'N' end,
+       (select upper(value) from v$parameter where name = 'cluster_database')
+from gv$instance i
+order by 1;
+exit
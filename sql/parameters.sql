@@ -0,0 +1,43 @@
+-- Copyright (c) 2023 Sorint.lab S.p.A.
+
+-- This program is free software: you can redistribute it and/or modify
+-- it under the terms of the GNU General Public License as published by
+-- the Free Software Foundation, either version 3 of the License, or
+-- (at your option) any later version.
+
+-- This program is distributed in the hope that it will be useful,
+-- but WITHOUT ANY WARRANTY; without even the implied warranty of
+-- MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+-- GNU General Public License for more details.
+
+-- You should have received a copy of the GNU General Public License
+-- along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+set lines 32767 pages 0 feedback off verify off timing off
+set colsep "|||"
+
+select 'INSTANCE',
+       instance_name
+from v$instance;
+
+select 'PARAMETER',
+       name,
+       value,
+       display_value,
+       isdefault,
+       ismodified,
+       isbasic
+from v$parameter
+order by name;
+
+-- Multi-valued parameters have a row for each value. Display values are compared,
+-- they are written with the same unit, e.g. 2G, in the spfile and in memory
+select 'SPFILE',
+       name,
+       sid,
+       ordinal,
+       display_value
+from v$spparameter
+where isspecified = 'TRUE'
+order by name, sid, ordinal;
+exit
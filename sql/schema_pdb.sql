@@ -27,7 +27,7 @@ alter session set container=&1;
 
 with 
 lista_users as
-(select username from dba_users where username not in ('SYS','AUDSYS','SYSTEM','SYSBACKUP','SYSDG','SYSKM','OUTLN','GSMADMIN_INTERNAL',
+(select username,account_status from dba_users where username not in ('SYS','AUDSYS','SYSTEM','SYSBACKUP','SYSDG','SYSKM','OUTLN','GSMADMIN_INTERNAL',
 'GSMUSER','DIP','XS$NULL','ORACLE_OCM','DBSNMP','APPQOSSYS','ANONYMOUS','XDB',
 'GSMCATUSER','WMSYS','OJVMSYS','CTXSYS','ORDDATA','ORDSYS','ORDPLUGINS',
 'SI_INFORMTN_SCHEMA','MDSYS','OLAPSYS','MDDATA','SPATIAL_WFS_ADMIN_USR',
@@ -61,13 +61,14 @@ select
 	   nvl(round(sum(s.bytes/1024/1024)),0) as "TOTMB",
 	   nvl(t.A,0) as "TBMB",
 	   nvl(i.B,0) as "INDMB",
-	   nvl(l.C,0) as "LOBMB"
+	   nvl(l.C,0) as "LOBMB",
+	   u.account_status
 	   from 
 	   lista_users u 
 	   left join dba_segments s on  u.username=s.owner
 	   left join tbmb t on  u.username=t.owner
 	   left join indmb i on  u.username=i.owner
 	   left join lobmb l on  u.username=l.owner
-	   group by u.username,t.a,i.b,l.c order by 1;
+	   group by u.username,u.account_status,t.a,i.b,l.c order by 1;
 
 exit
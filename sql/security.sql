@@ -0,0 +1,43 @@
+-- Copyright (c) 2023 Sorint.lab S.p.A.
+
+-- This program is free software: you can redistribute it and/or modify
+-- it under the terms of the GNU General Public License as published by
+-- the Free Software Foundation, either version 3 of the License, or
+-- (at your option) any later version.
+
+-- This program is distributed in the hope that it will be useful,
+-- but WITHOUT ANY WARRANTY; without even the implied warranty of
+-- MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+-- GNU General Public License for more details.
+
+-- You should have received a copy of the GNU General Public License
+-- along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+set lines 8000 pages 0 feedback off verify off timing off
+set colsep "|||"
+
+select 'USER',
+       username,
+       account_status,
+       to_char(lock_date, 'YYYY-MM-DD HH24:MI:SS'),
+       to_char(expiry_date, 'YYYY-MM-DD HH24:MI:SS'),
+       profile,
+       password_versions,
+       authentication_type,
+       to_char(last_login, 'YYYY-MM-DD HH24:MI:SS TZH:TZM')
+from dba_users
+order by username;
+
+select 'PROFILE',
+       profile,
+       resource_name,
+       limit
+from dba_profiles
+where resource_type = 'PASSWORD'
+order by profile, resource_name;
+
+select 'DEFPWD',
+       username
+from dba_users_with_defpwd
+order by username;
+exit
@@ -0,0 +1,119 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package status keeps track of the state of the agent while it's running
+package status
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ercole-io/ercole-agent-rhel5/model"
+)
+
+// Send results
+const (
+	SendResultSuccess = "SUCCESS"
+	SendResultFailed  = "FAILED"
+)
+
+// Status holds the state of the agent, it's safe for concurrent use
+type Status struct {
+	lock     sync.RWMutex
+	snapshot Snapshot
+}
+
+// Snapshot is a copy of the state of the agent at a given time
+type Snapshot struct {
+	Collections            uint64
+	LastCollection         time.Time
+	LastCollectionDuration float64
+	Databases              int
+	FetcherErrors          map[string]uint64
+	LastSend               time.Time
+	LastSendResult         string
+	LastSendHTTPStatus     int
+	NextRun                time.Time
+}
+
+// New return a Status of an agent that hasn't collected anything yet
+func New() *Status {
+	return &Status{
+		snapshot: Snapshot{
+			FetcherErrors: make(map[string]uint64),
+		},
+	}
+}
+
+// CollectionCompleted update the status with the hostdata just collected
+func (s *Status) CollectionCompleted(hostData *model.HostData) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.snapshot.Collections++
+	s.snapshot.LastCollection = time.Now()
+
+	s.snapshot.LastCollectionDuration = 0
+	if hostData.CollectionMetadata != nil {
+		s.snapshot.LastCollectionDuration = hostData.CollectionMetadata.Duration
+	}
+
+	s.snapshot.Databases = 0
+	if hostData.Features.Oracle != nil && hostData.Features.Oracle.Database != nil {
+		s.snapshot.Databases = len(hostData.Features.Oracle.Database.Databases)
+	}
+
+	for _, collectionError := range hostData.CollectionErrors {
+		name := collectionError.Fetcher
+		if name == "" {
+			name = collectionError.Section
+		}
+
+		s.snapshot.FetcherErrors[name]++
+	}
+}
+
+// SendCompleted update the status with the result of the last send of the hostdata.
+// httpStatus is 0 if no response was received.
+func (s *Status) SendCompleted(result string, httpStatus int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.snapshot.LastSend = time.Now()
+	s.snapshot.LastSendResult = result
+	s.snapshot.LastSendHTTPStatus = httpStatus
+}
+
+// SetNextRun update the time of the next scheduled collection
+func (s *Status) SetNextRun(nextRun time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.snapshot.NextRun = nextRun
+}
+
+// Snapshot return a copy of the current status
+func (s *Status) Snapshot() Snapshot {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	snapshot := s.snapshot
+	snapshot.FetcherErrors = make(map[string]uint64, len(s.snapshot.FetcherErrors))
+	for name, count := range s.snapshot.FetcherErrors {
+		snapshot.FetcherErrors[name] = count
+	}
+
+	return snapshot
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// +build !windows
+
+package utils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/ercole-io/ercole-agent-rhel5/logger"
+)
+
+const (
+	lockFileName = "ercole-agent.lock"
+	pidFileName  = "ercole-agent.pid"
+)
+
+// InstanceLock is the lock held by the running instance of the agent
+type InstanceLock struct {
+	file    *os.File
+	pidFile string
+}
+
+// InstanceLockedError is returned when another instance of the agent holds the lock
+type InstanceLockedError struct {
+	LockFile string
+	PID      string
+}
+
+func (e *InstanceLockedError) Error() string {
+	return fmt.Sprintf("Another instance of the agent (pid [%s]) holds the lock [%s], use --force to take it over if that agent is gone",
+		e.PID, e.LockFile)
+}
+
+// LockInstance take the lock of the agent in runDirectory and write the PID file.
+// The kernel releases the lock when its holder exits, so a held lock is stale only if the agent which wrote
+// its PID is gone while another process still has the lock file open, e.g. an orphaned child
+// which inherited the descriptor. If force is true such a stale lock is taken over: the lock file is replaced
+// so the old holder keeps a lock on a file that no longer exists. A lock whose agent is still running is never taken over.
+func LockInstance(log logger.Logger, runDirectory string, force bool) (*InstanceLock, error) {
+	lockFile := filepath.Join(runDirectory, lockFileName)
+	pidFile := filepath.Join(runDirectory, pidFileName)
+
+	file, err := openAndLock(lockFile)
+	if lockedErr, ok := err.(*InstanceLockedError); ok && force {
+		if processRunning(lockedErr.PID) {
+			return nil, fmt.Errorf("Can't take over the lock [%s]: the agent with pid [%s] is still running, stop it first",
+				lockFile, lockedErr.PID)
+		}
+
+		log.Warnf("Taking over the lock [%s] held by pid [%s]", lockFile, lockedErr.PID)
+
+		if err := os.Remove(lockFile); err != nil {
+			return nil, fmt.Errorf("Can't remove lock file [%s]: %v", lockFile, err)
+		}
+
+		file, err = openAndLock(lockFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pid := []byte(strconv.Itoa(os.Getpid()) + "\n")
+
+	if err := file.Truncate(0); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("Can't write lock file [%s]: %v", lockFile, err)
+	}
+	if _, err := file.WriteAt(pid, 0); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("Can't write lock file [%s]: %v", lockFile, err)
+	}
+
+	if err := ioutil.WriteFile(pidFile, pid, 0644); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("Can't write PID file [%s]: %v", pidFile, err)
+	}
+
+	return &InstanceLock{file: file, pidFile: pidFile}, nil
+}
+
+// processRunning returns true unless pid is a valid PID of a process which doesn't exist.
+// An unknown PID is handled as running, the holder may not have written it yet
+func processRunning(pid string) bool {
+	n, err := strconv.Atoi(pid)
+	if err != nil || n <= 0 {
+		return true
+	}
+
+	// EPERM means the process exists but belongs to another user
+	return syscall.Kill(n, 0) != syscall.ESRCH
+}
+
+func openAndLock(lockFile string) (*os.File, error) {
+	file, err := os.OpenFile(lockFile, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("Can't open lock file [%s]: %v", lockFile, err)
+	}
+
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		defer file.Close()
+
+		if err == syscall.EWOULDBLOCK {
+			pid, _ := ioutil.ReadAll(file)
+			return nil, &InstanceLockedError{LockFile: lockFile, PID: strings.TrimSpace(string(pid))}
+		}
+
+		return nil, fmt.Errorf("Can't lock file [%s]: %v", lockFile, err)
+	}
+
+	return file, nil
+}
+
+// Unlock remove the PID file, unless another instance has taken over it, and release the lock
+func (l *InstanceLock) Unlock() {
+	if pid, err := ioutil.ReadFile(l.pidFile); err == nil && strings.TrimSpace(string(pid)) == strconv.Itoa(os.Getpid()) {
+		os.Remove(l.pidFile)
+	}
+
+	syscall.Flock(int(l.file.Fd()), syscall.LOCK_UN)
+	l.file.Close()
+}
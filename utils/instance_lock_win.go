@@ -0,0 +1,32 @@
+// Copyright (c) 2023 Sorint.lab S.p.A.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// +build windows
+
+package utils
+
+import "github.com/ercole-io/ercole-agent-rhel5/logger"
+
+// InstanceLock is the lock held by the running instance of the agent
+type InstanceLock struct{}
+
+// LockInstance does nothing, locking the instance isn't supported on windows
+func LockInstance(log logger.Logger, runDirectory string, force bool) (*InstanceLock, error) {
+	return &InstanceLock{}, nil
+}
+
+// Unlock release the lock
+func (l *InstanceLock) Unlock() {
+}
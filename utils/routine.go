@@ -21,27 +21,72 @@ import (
 	"github.com/ercole-io/ercole-agent-rhel5/config"
 )
 
-// RunRoutine will run function in a separeted goroutine and wait or not due to config.
-func RunRoutine(configuration config.Configuration, function func()) {
-	if configuration.ParallelizeRequests {
-		go function()
-	} else {
+// WorkerPool runs functions in separated goroutines, at most size of them at the same time.
+// Functions are run synchronously if requests aren't parallelized by config.
+type WorkerPool struct {
+	parallelize bool
+	slots       chan struct{}
+}
+
+// NewWorkerPool return a WorkerPool that runs at most size functions at the same time
+func NewWorkerPool(configuration config.Configuration, size uint) *WorkerPool {
+	if size == 0 {
+		size = 1
+	}
+
+	return &WorkerPool{
+		parallelize: configuration.ParallelizeRequests,
+		slots:       make(chan struct{}, size),
+	}
+}
+
+// Run will run function in a separated goroutine as soon as a slot of the pool is free,
+// the caller is blocked until then.
+func (p *WorkerPool) Run(function func()) {
+	if !p.parallelize {
 		function()
+		return
 	}
+
+	p.slots <- struct{}{}
+
+	go func() {
+		defer func() { <-p.slots }()
+
+		function()
+	}()
 }
 
-// RunRoutineInGroup will run function in a separeted goroutine and wait or not due to config.
+// RunInGroup will run function like Run.
 // Increment waitGroup counter and notify when done.
-func RunRoutineInGroup(configuration config.Configuration, function func(), waitGroup *sync.WaitGroup) {
+func (p *WorkerPool) RunInGroup(function func(), waitGroup *sync.WaitGroup) {
 	waitGroup.Add(1)
 
-	if configuration.ParallelizeRequests {
-		go func() {
-			function()
-			waitGroup.Done()
-		}()
-	} else {
+	p.Run(func() {
+		defer waitGroup.Done()
+
 		function()
-		waitGroup.Done()
+	})
+}
+
+// Limiter bounds the functions run at the same time, by any goroutine, to its size
+type Limiter struct {
+	slots chan struct{}
+}
+
+// NewLimiter return a Limiter that runs at most size functions at the same time
+func NewLimiter(size uint) *Limiter {
+	if size == 0 {
+		size = 1
 	}
+
+	return &Limiter{slots: make(chan struct{}, size)}
+}
+
+// Do run function in the calling goroutine as soon as a slot is free
+func (l *Limiter) Do(function func()) {
+	l.slots <- struct{}{}
+	defer func() { <-l.slots }()
+
+	function()
 }